@@ -0,0 +1,247 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AppendValuesOptions are the options of a AppendValuesLoader.
+type AppendValuesOptions struct {
+	// BatchSize is the number of rows loaded in one round-trip (and one transaction).
+	// Defaults to DefaultBatchLimit.
+	BatchSize int
+	// MaxRejects is the number of rejected rows tolerated before the load is aborted.
+	// Negative means no limit.
+	//
+	// Note that the zero value tolerates no rejects: the load is aborted with ErrTooManyRejects
+	// on the first rejected row (the good rows of its batch are committed nevertheless) - set it to -1
+	// for loading all the good rows.
+	MaxRejects int
+}
+
+// RejectedRow is a row rejected by the database during an AppendValuesLoader load.
+type RejectedRow struct {
+	Err *OraErr
+	// Row is the index of the row, counting from 0 from the start of the load.
+	Row int64
+}
+
+// ErrTooManyRejects is returned by AppendValuesLoader when the number of rejected rows exceeds MaxRejects.
+var ErrTooManyRejects = errors.New("too many rejected rows")
+
+// AppendValuesLoader loads rows into a table with array INSERT /*+ APPEND_VALUES */ statements:
+// the database writes the rows as formatted blocks above the high water mark of the table,
+// bypassing the buffer cache, generating minimal undo (and no redo for NOLOGGING tables).
+//
+// This is NOT the OCI direct path load API (used by SQL*Loader), which is not available through ODPI-C,
+// but array DML with the direct-path INSERT hint.
+//
+// Each batch is inserted with one array INSERT /*+ APPEND_VALUES */ statement,
+// and committed - just like SQL*Loader's data saves.
+// If a batch fails, it is loaded again with conventional path INSERT in batch error mode,
+// so the good rows are loaded, and the rejected ones are reported by Rejected.
+//
+// The connection must not be in a transaction, and must not be used concurrently while loading.
+//
+// Restrictions of direct-path INSERT apply: with enabled triggers or foreign keys
+// on the table, the database silently falls back to conventional INSERT.
+type AppendValuesLoader struct {
+	conn               *conn
+	direct, fallback   *statement
+	table              string
+	columns            []string
+	rows               rowCollector
+	rejected           []RejectedRow
+	loaded, sent       int64
+	batchSize, maxRejs int
+}
+
+// AppendValuesConn is implemented by the connections (see Raw), for creating AppendValuesLoaders.
+type AppendValuesConn interface {
+	NewAppendValuesLoader(ctx context.Context, table string, columns []string, opts AppendValuesOptions) (*AppendValuesLoader, error)
+}
+
+var _ AppendValuesConn = (*conn)(nil)
+
+// NewAppendValuesLoader returns a AppendValuesLoader for the columns of the table.
+//
+// The table and column names are used verbatim in the INSERT statement, so they must be quoted if needed.
+func (c *conn) NewAppendValuesLoader(ctx context.Context, table string, columns []string, opts AppendValuesOptions) (*AppendValuesLoader, error) {
+	if table == "" || len(columns) == 0 {
+		return nil, errors.New("table and columns must be given")
+	}
+	if c.inTransaction {
+		return nil, errors.New("APPEND_VALUES load is not possible in a transaction")
+	}
+	l := AppendValuesLoader{
+		conn: c, table: table, columns: columns,
+		batchSize: opts.BatchSize, maxRejs: opts.MaxRejects,
+	}
+	if l.batchSize <= 0 {
+		l.batchSize = DefaultBatchLimit
+	}
+	st, err := c.PrepareContext(ctx, l.insertQuery("/*+ APPEND_VALUES */ "))
+	if err != nil {
+		return nil, err
+	}
+	l.direct = st.(*statement)
+	return &l, nil
+}
+
+func (l *AppendValuesLoader) insertQuery(hint string) string {
+	var buf strings.Builder
+	buf.WriteString("INSERT " + hint + "INTO " + l.table + " (" + strings.Join(l.columns, ", ") + ") VALUES (")
+	for i := range l.columns {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, ":%d", i+1)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// AddRow adds one row, with the values of the columns.
+// All rows must have the same types of values - use sql.NullString, sql.NullInt64 etc. for NULLs.
+//
+// The rows are loaded when BatchSize rows have been collected.
+func (l *AppendValuesLoader) AddRow(ctx context.Context, values ...interface{}) error {
+	if len(values) != len(l.columns) {
+		return fmt.Errorf("got %d values for %d columns", len(values), len(l.columns))
+	}
+	if err := l.rows.add(values); err != nil {
+		return fmt.Errorf("row %d: %w", l.sent+int64(l.rows.Len()), err)
+	}
+	if l.rows.Len() < l.batchSize {
+		return nil
+	}
+	return l.Flush(ctx)
+}
+
+// AddColumns adds rows given as slices of column values - the slices must have the same length.
+//
+// The collected rows are loaded first, then columns are loaded in batches of BatchSize.
+func (l *AppendValuesLoader) AddColumns(ctx context.Context, columns ...interface{}) error {
+	if len(columns) != len(l.columns) {
+		return fmt.Errorf("got %d slices for %d columns", len(columns), len(l.columns))
+	}
+	if err := l.Flush(ctx); err != nil {
+		return err
+	}
+	rColumns := make([]reflect.Value, len(columns))
+	n := -1
+	for i, c := range columns {
+		rColumns[i] = reflect.ValueOf(c)
+		if rColumns[i].Kind() != reflect.Slice {
+			return fmt.Errorf("column %d: got %T, wanted a slice", i, c)
+		}
+		if n < 0 {
+			n = rColumns[i].Len()
+		} else if m := rColumns[i].Len(); m != n {
+			return fmt.Errorf("column %d has %d rows, column 0 has %d", i, m, n)
+		}
+	}
+	args := make([]interface{}, len(columns))
+	for start := 0; start < n; start += l.batchSize {
+		end := minI(start+l.batchSize, n)
+		for i, c := range rColumns {
+			args[i] = c.Slice(start, end).Interface()
+		}
+		if err := l.load(ctx, args, end-start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush loads the collected rows.
+func (l *AppendValuesLoader) Flush(ctx context.Context) error {
+	n := l.rows.Len()
+	if n == 0 {
+		return nil
+	}
+	err := l.load(ctx, l.rows.Values(), n)
+	l.rows.Reset()
+	return err
+}
+
+// load the n rows of the column slices, in one batch.
+func (l *AppendValuesLoader) load(ctx context.Context, columns []interface{}, n int) error {
+	if l.direct == nil {
+		return errors.New("AppendValuesLoader is closed")
+	}
+	offset := l.sent
+	l.sent += int64(n)
+	args := make([]driver.NamedValue, len(columns))
+	for i, c := range columns {
+		args[i] = driver.NamedValue{Ordinal: i + 1, Value: c}
+	}
+	_, err := l.direct.ExecContext(ctx, args)
+	if err == nil {
+		l.loaded += int64(n)
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if _, ok := AsOraErr(err); !ok {
+		return err
+	}
+	logger := l.conn.getLogger(ctx)
+	if logger != nil {
+		logger.Warn("APPEND_VALUES load failed, reloading with conventional path", "table", l.table, "offset", offset, "rows", n, "error", err)
+	}
+	// The failed statement has been rolled back. Load the batch again with conventional path,
+	// collecting the errors of the rejected rows.
+	if l.fallback == nil {
+		st, err := l.conn.PrepareContext(ctx, l.insertQuery(""))
+		if err != nil {
+			return err
+		}
+		l.fallback = st.(*statement)
+	}
+	var batchErrors []*OraErr
+	l.fallback.batchErrors = &batchErrors
+	_, err = l.fallback.ExecContext(ctx, args)
+	if err != nil {
+		return err
+	}
+	for _, oe := range batchErrors {
+		l.rejected = append(l.rejected, RejectedRow{Row: offset + int64(oe.Offset()), Err: oe})
+	}
+	l.loaded += int64(n - len(batchErrors))
+	if l.maxRejs >= 0 && len(l.rejected) > l.maxRejs {
+		return multiErrorf("%w: %d rows, first at row %d: %w", "%w: %d rows, first at row %d: %v",
+			ErrTooManyRejects, len(l.rejected), l.rejected[0].Row, l.rejected[0].Err)
+	}
+	return nil
+}
+
+// Loaded returns the number of rows loaded so far.
+func (l *AppendValuesLoader) Loaded() int64 { return l.loaded }
+
+// Rejected returns the rows rejected so far.
+func (l *AppendValuesLoader) Rejected() []RejectedRow { return l.rejected }
+
+// Close loads the remaining collected rows, and releases the resources.
+func (l *AppendValuesLoader) Close(ctx context.Context) error {
+	err := l.Flush(ctx)
+	for _, st := range []*statement{l.direct, l.fallback} {
+		if st != nil {
+			if closeErr := st.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	}
+	l.direct, l.fallback = nil, nil
+	return err
+}
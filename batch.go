@@ -8,92 +8,345 @@ package godror
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"sync"
+	"time"
 )
 
 const DefaultBatchLimit = 1024
 
-// Batch collects the Added rows and executes in batches, after collecting Limit number of rows.
+// Batch collects the Added rows and executes in batches, after collecting Limit number of rows,
+// or after FlushInterval elapsed since the first collected row.
 // The default Limit is DefaultBatchLimit.
+//
+// The values of each column must have the same type in every row, checked by Add.
+// NULLs can be given with pointers (nil) or sql.Null* types (Valid=false).
+//
+// Batch is safe for concurrent use.
 type Batch struct {
-	Stmt        *sql.Stmt
-	values      []interface{}
-	rValues     []reflect.Value
-	size, Limit int
+	Stmt *sql.Stmt
+	// flushErr is the error of the last background (FlushInterval) flush.
+	flushErr error
+	timer    *time.Timer
+	rows     rowCollector
+	Limit    int
+	// FlushInterval, if positive, is the maximum time the rows are kept before they're flushed in the background.
+	// The background flush uses the context of the Add that started its timer,
+	// and its error is returned by the next Add (which still adds its row) or Flush.
+	// If that context is canceled, the next Add starts a new timer.
+	FlushInterval time.Duration
+	mu            sync.Mutex
+	// BatchErrors makes Flush use batch error mode (see CollectBatchErrors):
+	// the good rows are inserted, and the failed rows are returned in a *BatchError.
+	BatchErrors bool
+}
+
+// BatchError is returned by Batch.Flush in batch error mode, with the failed rows.
+type BatchError struct {
+	Rows []BatchRowError
+}
+
+// BatchRowError is a row failed in batch error mode.
+type BatchRowError struct {
+	Err *OraErr
+	// Values of the row, as Added (with NULLs converted).
+	Values []interface{}
+	// Index of the row in the flushed batch.
+	Index int
+}
+
+func (be *BatchError) Error() string {
+	if len(be.Rows) == 0 {
+		return "no batch errors"
+	}
+	return fmt.Sprintf("%d rows failed, first (%d.): %v", len(be.Rows), be.Rows[0].Index, be.Rows[0].Err)
+}
+
+// Unwrap returns the error of the first failed row.
+func (be *BatchError) Unwrap() error {
+	if len(be.Rows) == 0 {
+		return nil
+	}
+	return be.Rows[0].Err
 }
 
 // Add the values. The first call initializes the storage,
 // so all the subsequent calls to Add must use the same number of values,
-// with the same types.
+// with the same types - a mismatch is returned as an error, and the row is not added.
 //
 // When the number of added rows reaches Size, Flush is called.
+//
+// If the previous background (FlushInterval) flush has failed, the row is still added,
+// and the error of that flush is returned, wrapped in "previous background flush failed".
 func (b *Batch) Add(ctx context.Context, values ...interface{}) error {
-	if b.rValues == nil {
-		if b.Limit <= 0 {
-			b.Limit = DefaultBatchLimit
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Limit <= 0 {
+		b.Limit = DefaultBatchLimit
+	}
+	if err := b.rows.add(values); err != nil {
+		return fmt.Errorf("add %d. row: %w", b.rows.Len(), err)
+	}
+	var err error
+	if b.rows.Len() >= b.Limit {
+		err = b.flush(ctx)
+	} else if b.timer == nil && b.FlushInterval > 0 {
+		b.startTimer(ctx)
+	}
+	prevErr := b.flushErr
+	if prevErr == nil {
+		return err
+	}
+	b.flushErr = nil
+	if err == nil {
+		return fmt.Errorf("previous background flush failed: %w", prevErr)
+	}
+	return multiErrorf("%w (previous background flush failed: %w)", "%w (previous background flush failed: %v)", err, prevErr)
+}
+
+// startTimer starts the background flush of the rows, after FlushInterval.
+func (b *Batch) startTimer(ctx context.Context) {
+	var timer *time.Timer
+	timer = time.AfterFunc(b.FlushInterval, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.timer != timer {
+			return // flushed meanwhile
 		}
-		b.rValues = make([]reflect.Value, len(values))
-		for i := range values {
-			b.rValues[i] = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(values[i])), 0, b.Limit)
+		b.timer = nil
+		if ctx.Err() != nil {
+			return
 		}
-	}
-	func() {
-		var i int
-		var v any
-		defer func() {
-			if r := recover(); r != nil {
-				panic(fmt.Errorf("append %#v (%d.) to %#v: %+v", v, i, b.rValues[i], r))
-			}
-		}()
-		for i, v = range values {
-			rv := reflect.ValueOf(v)
-			// type mismatch
-			if rv.Type().Kind() != reflect.String &&
-				b.rValues[i].Type().Elem().Kind() == reflect.String {
-				vv := b.rValues[i]
-				// fmt.Println("rv", vv.Interface())
-				allZero := true
-				for j := 0; j < vv.Len(); j++ {
-					if allZero = vv.Index(j).Len() == 0; !allZero {
-						break
-					}
-				}
-				if allZero { // all zero, replace with proper typed slice
-					b.rValues[i] = reflect.MakeSlice(reflect.SliceOf(rv.Type()), vv.Len(), vv.Cap())
-				}
-				// fmt.Println("allZero?", allZero, "vv", b.rValues[i].Interface())
-			}
-			b.rValues[i] = reflect.Append(b.rValues[i], rv)
+		if err := b.flush(ctx); err != nil && b.flushErr == nil {
+			b.flushErr = err
 		}
-	}()
-	b.size++
-	if b.size < b.Limit {
-		return nil
-	}
-	return b.Flush(ctx)
+	})
+	b.timer = timer
 }
 
 // Size returns the buffered (unflushed) number of records.
-func (b *Batch) Size() int { return b.size }
+func (b *Batch) Size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rows.Len()
+}
 
 // Flush executes the statement is and the clears the storage.
+//
+// On error, the rows are kept for a retry - except in batch error mode,
+// where the good rows are inserted, and the failed ones are returned in a *BatchError.
 func (b *Batch) Flush(ctx context.Context) error {
-	if len(b.rValues) == 0 || b.rValues[0].Len() == 0 {
-		return nil
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	err := b.flush(ctx)
+	if err == nil {
+		err, b.flushErr = b.flushErr, nil
+	}
+	return err
+}
+
+func (b *Batch) flush(ctx context.Context) error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
 	}
-	if b.values == nil {
-		b.values = make([]interface{}, len(b.rValues))
+	if b.rows.Len() == 0 {
+		return nil
 	}
-	for i, v := range b.rValues {
-		b.values[i] = v.Interface()
+	values := b.rows.Values()
+	if !b.BatchErrors {
+		if _, err := b.Stmt.ExecContext(ctx, values...); err != nil {
+			return err
+		}
+		b.rows.Reset()
+		return nil
 	}
-	if _, err := b.Stmt.ExecContext(ctx, b.values...); err != nil {
+
+	var batchErrors []*OraErr
+	args := append(append(make([]interface{}, 0, len(values)+1), values...), CollectBatchErrors(&batchErrors))
+	if _, err := b.Stmt.ExecContext(ctx, args...); err != nil {
 		return err
 	}
-	for i, v := range b.rValues {
-		b.rValues[i] = v.Slice(0, 0)
+	var be BatchError
+	for _, oe := range batchErrors {
+		be.Rows = append(be.Rows, BatchRowError{Index: oe.Offset(), Err: oe, Values: b.rows.Row(oe.Offset())})
+	}
+	b.rows.Reset()
+	if len(be.Rows) != 0 {
+		return &be
+	}
+	return nil
+}
+
+// rowCollector collects rows into typed slices, one per column, for array DML.
+type rowCollector struct {
+	columns []reflect.Value
+	values  []interface{}
+	n       int
+}
+
+// Len returns the number of collected rows.
+func (rc *rowCollector) Len() int { return rc.n }
+
+// add a row. The first row determines the types of the columns.
+//
+// Pointers and sql.Null* values are converted to bindable types (see bindableNull).
+func (rc *rowCollector) add(values []interface{}) error {
+	if rc.columns != nil && len(values) != len(rc.columns) {
+		return fmt.Errorf("got %d values, wanted %d", len(values), len(rc.columns))
+	}
+	converted := make([]interface{}, len(values))
+	for i, v := range values {
+		var err error
+		if converted[i], err = bindableNull(v); err != nil {
+			return fmt.Errorf("column %d: %w", i, err)
+		}
+	}
+	if rc.columns == nil {
+		columns := make([]reflect.Value, len(values))
+		for i, v := range converted {
+			if v == nil {
+				return fmt.Errorf("column %d: type of nil is unknown - use a typed nil pointer or sql.Null*", i)
+			}
+			columns[i] = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(v)), 0, DefaultBatchLimit)
+		}
+		rc.columns = columns
+	}
+	for i, v := range converted {
+		want := rc.columns[i].Type().Elem()
+		if v == nil {
+			if !isNullable(want) {
+				return fmt.Errorf("column %d: nil is not allowed for %s", i, want)
+			}
+			continue
+		}
+		if got := reflect.TypeOf(v); got != want {
+			return fmt.Errorf("column %d: got %s, wanted %s", i, got, want)
+		}
+	}
+	for i, v := range converted {
+		if v == nil {
+			rc.columns[i] = reflect.Append(rc.columns[i], reflect.Zero(rc.columns[i].Type().Elem()))
+		} else {
+			rc.columns[i] = reflect.Append(rc.columns[i], reflect.ValueOf(v))
+		}
 	}
-	b.size = 0
+	rc.n++
 	return nil
 }
+
+// Row returns the values of the i-th collected row.
+func (rc *rowCollector) Row(i int) []interface{} {
+	if i < 0 || i >= rc.n {
+		return nil
+	}
+	row := make([]interface{}, len(rc.columns))
+	for j, c := range rc.columns {
+		row[j] = c.Index(i).Interface()
+	}
+	return row
+}
+
+// Values returns the column slices.
+func (rc *rowCollector) Values() []interface{} {
+	if rc.values == nil {
+		rc.values = make([]interface{}, len(rc.columns))
+	}
+	for i, c := range rc.columns {
+		rc.values[i] = c.Interface()
+	}
+	return rc.values
+}
+
+// Reset the collected rows, keeping the column types and storage.
+func (rc *rowCollector) Reset() {
+	for i, c := range rc.columns {
+		rc.columns[i] = c.Slice(0, 0)
+	}
+	rc.n = 0
+}
+
+var (
+	typeNullInt64       = reflect.TypeOf(sql.NullInt64{})
+	typeNullInt32       = reflect.TypeOf(sql.NullInt32{})
+	typeNullFloat64     = reflect.TypeOf(sql.NullFloat64{})
+	typeNullTime        = reflect.TypeOf(NullTime{})
+	errNullNotSupported = errors.New("NULL is not supported")
+)
+
+// isNullable reports whether the zero value of the column type means NULL.
+func isNullable(typ reflect.Type) bool {
+	switch typ {
+	case typeNullInt64, typeNullInt32, typeNullFloat64, typeNullTime:
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Slice, reflect.Ptr, reflect.Interface:
+		return true // the empty string is NULL
+	}
+	return false
+}
+
+// bindableNull converts the pointer and sql.Null* values to types which can be bound as arrays:
+// *string and sql.NullString to string (the empty string is NULL in Oracle),
+// pointers to integers to sql.NullInt64, pointers to floats to sql.NullFloat64,
+// *time.Time to NullTime.
+func bindableNull(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case sql.NullString:
+		return x.String, nil
+	case *string:
+		if x == nil {
+			return "", nil
+		}
+		return *x, nil
+	case *Number:
+		if x == nil {
+			return Number(""), nil
+		}
+		return *x, nil
+	case *time.Time:
+		if x == nil {
+			return NullTime{}, nil
+		}
+		return NullTime{Valid: true, Time: *x}, nil
+	case sql.NullBool, *bool:
+		return nil, fmt.Errorf("%T: %w", v, errNullNotSupported)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return v, nil
+	}
+	switch rv.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.IsNil() {
+			return sql.NullInt64{}, nil
+		}
+		return sql.NullInt64{Valid: true, Int64: rv.Elem().Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.IsNil() {
+			return sql.NullInt64{}, nil
+		}
+		u := rv.Elem().Uint()
+		if u > math.MaxInt64 {
+			return nil, fmt.Errorf("%d overflows int64", u)
+		}
+		return sql.NullInt64{Valid: true, Int64: int64(u)}, nil
+	case reflect.Float32, reflect.Float64:
+		if rv.IsNil() {
+			return sql.NullFloat64{}, nil
+		}
+		return sql.NullFloat64{Valid: true, Float64: rv.Elem().Float()}, nil
+	case reflect.String:
+		if rv.IsNil() {
+			return "", nil
+		}
+		return rv.Elem().String(), nil
+	}
+	return v, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRowCollector(t *testing.T) {
+	var rc rowCollector
+	s, i, f := "a", int32(1), 1.5
+	now := time.Now()
+	if err := rc.add([]interface{}{&s, &i, &f, &now, sql.NullString{String: "x", Valid: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.add([]interface{}{(*string)(nil), (*int32)(nil), nil, (*time.Time)(nil), sql.NullString{}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.add([]interface{}{"b", int32(2), 2.5, now, "y"}); err == nil {
+		t.Error("wanted type mismatch error")
+	} else {
+		t.Log(err)
+	}
+	if err := rc.add([]interface{}{"b"}); err == nil {
+		t.Error("wanted column count error")
+	}
+	if rc.Len() != 2 {
+		t.Errorf("got %d rows, wanted 2", rc.Len())
+	}
+	want := []interface{}{
+		[]string{"a", ""},
+		[]sql.NullInt64{{Int64: 1, Valid: true}, {}},
+		[]sql.NullFloat64{{Float64: 1.5, Valid: true}, {}},
+		[]NullTime{{Time: now, Valid: true}, {}},
+		[]string{"x", ""},
+	}
+	if got := rc.Values(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, wanted %#v", got, want)
+	}
+	if got, want := rc.Row(1), []interface{}{"", sql.NullInt64{}, sql.NullFloat64{}, NullTime{}, ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, wanted %#v", got, want)
+	}
+	rc.Reset()
+	if rc.Len() != 0 {
+		t.Errorf("got %d rows after Reset", rc.Len())
+	}
+
+	var rc2 rowCollector
+	if err := rc2.add([]interface{}{nil}); err == nil {
+		t.Error("wanted error for untyped nil in the first row")
+	}
+	if err := rc2.add([]interface{}{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rc2.add([]interface{}{nil}); err == nil {
+		t.Error("wanted error for nil int")
+	}
+	if err := rc2.add([]interface{}{new(bool)}); err == nil {
+		t.Error("wanted error for *bool")
+	}
+}
+
+func TestCheckMergeKeys(t *testing.T) {
+	t.Parallel()
+	type row struct {
+		ID   int64
+		Code *string
+		Text string
+	}
+	a, b := "A", "B"
+	fields, isKey := []int{0, 1, 2}, []bool{true, true, false}
+	for i, tc := range []struct {
+		Rows    []row
+		WantErr bool
+	}{
+		{Rows: []row{{ID: 1, Code: &a}, {ID: 1, Code: &b}, {ID: 2, Code: &a}, {ID: 1}}},
+		{Rows: []row{{ID: 1, Code: &a, Text: "x"}, {ID: 2}, {ID: 1, Code: &a, Text: "y"}}, WantErr: true},
+		{Rows: []row{{ID: 1}, {ID: 1}}, WantErr: true},
+	} {
+		err := checkMergeKeys(reflect.ValueOf(tc.Rows), false, fields, isKey)
+		t.Logf("%d. %v", i, err)
+		if (err != nil) != tc.WantErr {
+			t.Errorf("%d. got error %v, wanted error: %t", i, err, tc.WantErr)
+		}
+	}
+}
+
+func TestBatchTimerRearm(t *testing.T) {
+	t.Parallel()
+	b := Batch{FlushInterval: time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Add(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	// the timer fires with the canceled context, without flushing
+	for i := 0; i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		b.mu.Lock()
+		stopped := b.timer == nil
+		b.mu.Unlock()
+		if stopped {
+			break
+		}
+	}
+	b.FlushInterval = time.Hour
+	if err := b.Add(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer == nil {
+		t.Fatal("timer is not re-armed")
+	}
+	b.timer.Stop()
+	if n := b.rows.Len(); n != 2 {
+		t.Errorf("got %d rows, wanted 2", n)
+	}
+}
+
+func TestBatchAddAfterFailedFlush(t *testing.T) {
+	t.Parallel()
+	db := sql.OpenDB(failExecConnector{})
+	defer db.Close()
+	stmt, err := db.Prepare("INSERT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	b := Batch{Stmt: stmt, FlushInterval: time.Millisecond}
+	ctx := context.Background()
+	if err := b.Add(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	// wait for the failing background flush
+	for i := 0; i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		b.mu.Lock()
+		failed := b.flushErr != nil
+		b.mu.Unlock()
+		if failed {
+			break
+		}
+	}
+	b.FlushInterval = time.Hour
+	err = b.Add(ctx, 2)
+	if !errors.Is(err, errExecFailed) {
+		t.Fatalf("got %+v, wanted %v", err, errExecFailed)
+	}
+	t.Log(err)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	if n := b.rows.Len(); n != 2 {
+		t.Errorf("got %d rows, wanted 2 (the failed and the new one)", n)
+	}
+	if b.flushErr != nil {
+		t.Errorf("flush error is kept: %+v", b.flushErr)
+	}
+}
+
+var errExecFailed = errors.New("exec failed")
+
+// failExecConnector's statements fail to execute.
+type failExecConnector struct{}
+
+func (fc failExecConnector) Connect(context.Context) (driver.Conn, error) { return fc, nil }
+func (fc failExecConnector) Driver() driver.Driver                        { return nil }
+func (fc failExecConnector) Prepare(string) (driver.Stmt, error)          { return fc, nil }
+func (fc failExecConnector) Close() error                                 { return nil }
+func (fc failExecConnector) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+func (fc failExecConnector) NumInput() int                            { return -1 }
+func (fc failExecConnector) CheckNamedValue(*driver.NamedValue) error { return nil }
+func (fc failExecConnector) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errExecFailed
+}
+func (fc failExecConnector) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errExecFailed
+}
@@ -0,0 +1,241 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include <stdlib.h>
+#include "dpiImpl.h"
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// BFile is a BFILE: a locator of a read-only, external binary file in a server-side directory.
+//
+// A BFile with Dir and Name set can be bound as an IN parameter, to insert or update a BFILE column:
+//
+//	db.ExecContext(ctx, "INSERT INTO docs (id, doc) VALUES (:1, :2)", 1, godror.BFile{Dir: "DOC_DIR", Name: "a.pdf"})
+//
+// For checking the existence of the file or reading it, the BFile must have a locator:
+// get it from BFileConn.NewBFile, as an OUT parameter, or by scanning a BFILE column into a *BFile.
+// Such a BFile must be closed after use.
+type BFile struct {
+	lr *dpiLobReader
+	// Dir is the name of the directory object (not the path!), Name is the file's name in that directory.
+	Dir, Name string
+}
+
+var _ = io.ReaderAt((*BFile)(nil))
+var _ = io.WriterTo((*BFile)(nil))
+
+var errBFileNoLocator = errors.New("BFile has no locator (use BFileConn.NewBFile)")
+
+// BFileConn is implemented by the connections (see Raw), for creating BFile locators.
+type BFileConn interface {
+	NewBFile(dir, name string) (*BFile, error)
+}
+
+var _ BFileConn = (*conn)(nil)
+
+// NewBFile returns a BFile locator for the dir directory object and the name file in it.
+//
+// The file does not have to exist. The returned BFile must be closed.
+func (c *conn) NewBFile(dir, name string) (*BFile, error) {
+	dv, data, err := c.newVar(varInfo{Typ: C.DPI_ORACLE_TYPE_BFILE, NatTyp: C.DPI_NATIVE_TYPE_LOB, SliceLen: 1})
+	if err != nil {
+		return nil, fmt.Errorf("NewBFile: %w", err)
+	}
+	lob := C.dpiData_getLOB(&data[0])
+	// The BFile holds its own reference, so the variable can be released.
+	C.dpiLob_addRef(lob)
+	C.dpiVar_release(dv)
+	bf := &BFile{lr: &dpiLobReader{drv: c.drv, dpiLob: lob}, Dir: dir, Name: name}
+	if err = bf.setFileName(c.drv, lob); err != nil {
+		C.dpiLob_release(lob)
+		return nil, err
+	}
+	return bf, nil
+}
+
+// setFileName sets the directory and file name of the locator.
+func (bf BFile) setFileName(d *drv, lob *C.dpiLob) error {
+	cDir, cName := C.CString(bf.Dir), C.CString(bf.Name)
+	defer func() { C.free(unsafe.Pointer(cDir)); C.free(unsafe.Pointer(cName)) }()
+	if err := d.checkExec(func() C.int {
+		return C.dpiLob_setDirectoryAndFileName(lob,
+			cDir, C.uint32_t(len(bf.Dir)),
+			cName, C.uint32_t(len(bf.Name)))
+	}); err != nil {
+		return fmt.Errorf("setDirectoryAndFileName(%q, %q): %w", bf.Dir, bf.Name, err)
+	}
+	return nil
+}
+
+// attach the BFile to the lob, holding a new reference on it, and fill Dir and Name.
+func (bf *BFile) attach(d *drv, lob *C.dpiLob) error {
+	bf.Close()
+	dl := DirectLob{drv: d, dpiLob: lob}
+	dir, name, err := dl.GetFileName()
+	if err != nil {
+		return err
+	}
+	if err = d.checkExec(func() C.int { return C.dpiLob_addRef(lob) }); err != nil {
+		return fmt.Errorf("addRef: %w", err)
+	}
+	bf.lr = &dpiLobReader{drv: d, dpiLob: lob}
+	bf.Dir, bf.Name = dir, name
+	return nil
+}
+
+// Scan a BFILE column into the BFile.
+//
+// The BFile must be closed after use.
+func (bf *BFile) Scan(src interface{}) error {
+	switch x := src.(type) {
+	case nil:
+		bf.Close()
+		bf.Dir, bf.Name = "", ""
+		return nil
+	case *Lob:
+		if x == nil || x.Reader == nil {
+			return bf.Scan(nil)
+		}
+		lr, ok := x.Reader.(*dpiLobReader)
+		if !ok || lr.dpiLob == nil {
+			return fmt.Errorf("cannot scan %T into BFile", x.Reader)
+		}
+		return bf.attach(lr.drv, lr.dpiLob)
+	default:
+		return fmt.Errorf("cannot scan %T into BFile", src)
+	}
+}
+
+// Exists reports whether the file exists on the server.
+func (bf *BFile) Exists() (bool, error) {
+	if bf.lr == nil || bf.lr.dpiLob == nil {
+		return false, errBFileNoLocator
+	}
+	var exists C.int
+	if err := bf.lr.checkExec(func() C.int { return C.dpiLob_getFileExists(bf.lr.dpiLob, &exists) }); err != nil {
+		return false, fmt.Errorf("getFileExists(%q, %q): %w", bf.Dir, bf.Name, err)
+	}
+	return exists == 1, nil
+}
+
+// Open the file on the server.
+//
+// This is optional, as reading opens the file if needed, but saves reopening it on each read.
+func (bf *BFile) Open() error {
+	if bf.lr == nil || bf.lr.dpiLob == nil {
+		return errBFileNoLocator
+	}
+	if err := bf.lr.checkExec(func() C.int { return C.dpiLob_openResource(bf.lr.dpiLob) }); err != nil {
+		return fmt.Errorf("open(%q, %q): %w", bf.Dir, bf.Name, err)
+	}
+	return nil
+}
+
+// IsOpen reports whether the file is opened.
+func (bf *BFile) IsOpen() (bool, error) {
+	if bf.lr == nil || bf.lr.dpiLob == nil {
+		return false, errBFileNoLocator
+	}
+	var isOpen C.int
+	if err := bf.lr.checkExec(func() C.int { return C.dpiLob_getIsResourceOpen(bf.lr.dpiLob, &isOpen) }); err != nil {
+		return false, fmt.Errorf("getIsResourceOpen: %w", err)
+	}
+	return isOpen == 1, nil
+}
+
+// Close the file (if opened) and release the locator.
+//
+// Dir and Name are kept, so the BFile still can be bound.
+func (bf *BFile) Close() error {
+	if bf == nil || bf.lr == nil {
+		return nil
+	}
+	lr := bf.lr
+	bf.lr = nil
+	return lr.Close()
+}
+
+// Size returns the size of the file.
+func (bf *BFile) Size() (int64, error) {
+	if bf.lr == nil || bf.lr.dpiLob == nil {
+		return 0, errBFileNoLocator
+	}
+	var n C.uint64_t
+	if err := bf.lr.checkExec(func() C.int { return C.dpiLob_getSize(bf.lr.dpiLob, &n) }); err != nil {
+		return 0, fmt.Errorf("getSize(%q, %q): %w", bf.Dir, bf.Name, err)
+	}
+	return int64(n), nil
+}
+
+// ReadAt reads len(p) bytes from the file at offset off.
+func (bf *BFile) ReadAt(p []byte, off int64) (int, error) {
+	if bf.lr == nil || bf.lr.dpiLob == nil {
+		return 0, errBFileNoLocator
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, err := bf.lr.ReadAt(p, off)
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// WriteTo writes the contents of the file to w.
+func (bf *BFile) WriteTo(w io.Writer) (int64, error) {
+	length, err := bf.Size()
+	if err != nil {
+		return 0, err
+	}
+	size := bf.lr.ChunkSize()
+	const minBufferSize = 1 << 20
+	if size <= 0 {
+		size = minBufferSize
+	} else {
+		for size < minBufferSize/2 {
+			size *= 2
+		}
+	}
+	return io.CopyBuffer(w, io.NewSectionReader(bf, 0, length), make([]byte, size))
+}
+
+func (c *conn) dataSetBFile(ctx context.Context, dv *C.dpiVar, data []C.dpiData, vv interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	bf, ok := vv.(BFile)
+	if !ok || bf.Dir == "" && bf.Name == "" {
+		return dataSetNull(ctx, dv, data, nil)
+	}
+	data[0].isNull = 0
+	lob := C.dpiData_getLOB(&data[0])
+	if lob == nil {
+		return fmt.Errorf("no BFILE locator in %p", dv)
+	}
+	return bf.setFileName(c.drv, lob)
+}
+
+func (c *conn) dataGetBFile(ctx context.Context, v interface{}, data []C.dpiData) error {
+	bf := v.(*BFile)
+	if len(data) == 0 || data[0].isNull == 1 {
+		return bf.Scan(nil)
+	}
+	lob := C.dpiData_getLOB(&data[0])
+	if lob == nil {
+		return bf.Scan(nil)
+	}
+	return bf.attach(c.drv, lob)
+}
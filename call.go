@@ -0,0 +1,444 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CallReturn is the name of the function's return value in the outputs of Call.
+const CallReturn = "RETURN"
+
+// ErrNoMatchingOverload is returned by Call when no overload of the program matches the given arguments.
+var ErrNoMatchingOverload = errors.New("no matching overload")
+
+// Call calls the stored procedure or function (as "proc", "pkg.proc" or "schema.pkg.proc"),
+// with the arguments given in args, and returns the outputs: the OUT and IN OUT arguments,
+// and the return value of a function (as CallReturn).
+//
+// args can be a map[string]interface{}, or a pointer to a struct, whose fields are
+// matched by their `godror:"name"` tag or their name (case insensitively) to the arguments of the program.
+// The unexported fields and the fields tagged `godror:"-"` are ignored, but all the other fields
+// (and map keys) must be arguments of the called overload, else ErrNoMatchingOverload is returned.
+//
+// The arguments are looked up in ALL_ARGUMENTS, and the program is called with named notation,
+// so the defaulted IN arguments can be omitted. The OUT arguments need not be given:
+// for a map, they're returned as
+//
+//	VARCHAR2, CHAR, CLOB (max. 32767 bytes)       string
+//	NUMBER, PLS_INTEGER                           Number (nil for NULL)
+//	BINARY_FLOAT, BINARY_DOUBLE                   float64
+//	DATE, TIMESTAMP                               time.Time (nil for NULL)
+//	INTERVAL DAY TO SECOND                        time.Duration
+//	RAW, BLOB (max. 32767 bytes)                  []byte
+//	BOOLEAN                                       bool
+//	REF CURSOR                                    driver.Rows
+//	OBJECT, RECORD, TABLE, VARRAY                 *Object
+//
+// for a struct, they're put into the struct's fields (and returned, too).
+//
+// The object, record and collection (TABLE/VARRAY) arguments can be given as *Object,
+// as struct (see the "type=" tag option of ObjectType conversions),
+// or as map[string]interface{} (for objects/records) and slices (for collections),
+// which are converted using the argument's type from GetObjectType.
+//
+// The returned *Objects and driver.Rows must be closed by the caller,
+// and ex must be a *sql.Conn or *sql.Tx when objects or REF CURSORs are used.
+func Call(ctx context.Context, ex ExecQuerier, name string, args interface{}) (map[string]interface{}, error) {
+	given, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	overloads, err := describeProgram(ctx, ex, name)
+	if err != nil {
+		return nil, err
+	}
+	program, err := selectOverload(overloads, given)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	var toClose []*Object
+	closeAll := func() {
+		for _, O := range toClose {
+			O.Close()
+		}
+	}
+	var argList strings.Builder
+	var retBind string
+	params := make([]interface{}, 0, len(program))
+	outputs := make(map[string]interface{}, len(program))
+	for _, arg := range program {
+		key := strings.ToUpper(arg.Name)
+		if arg.Position == 0 {
+			key = CallReturn
+		}
+		value, isGiven := given[key]
+		if !isGiven && !arg.IsOut {
+			continue // defaulted
+		}
+		bindName := "a" + strconv.Itoa(len(params)+1)
+		if arg.Position == 0 {
+			retBind = bindName
+		} else {
+			if argList.Len() != 0 {
+				argList.WriteString(", ")
+			}
+			argList.WriteString(quoteIdent(arg.Name) + "=>:" + bindName)
+		}
+
+		if arg.isObject() {
+			obj, err := callObject(ctx, ex, arg, value)
+			if err != nil {
+				closeAll()
+				return nil, fmt.Errorf("%s: %w", arg.Name, err)
+			}
+			if obj != nil {
+				toClose = append(toClose, obj)
+				value = obj
+			}
+		}
+		if cf, ok := value.(callField); ok {
+			value = cf.Ptr
+		}
+		if !arg.IsOut {
+			params = append(params, sql.Named(bindName, value))
+			continue
+		}
+
+		var dest interface{}
+		switch x := value.(type) {
+		case nil:
+			if dest, err = arg.outDest(); err != nil {
+				closeAll()
+				return nil, err
+			}
+		case *Object:
+			dest = x
+		default:
+			if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr {
+				dest = value // struct field
+			} else {
+				ptr := reflect.New(rv.Type())
+				ptr.Elem().Set(rv)
+				dest = ptr.Interface()
+			}
+		}
+		params = append(params, sql.Named(bindName, sql.Out{Dest: dest, In: arg.IsIn && isGiven}))
+		outputs[key] = dest
+	}
+	qry := "BEGIN " + name + "(" + argList.String() + "); END;"
+	if retBind != "" {
+		qry = "BEGIN :" + retBind + " := " + name + "(" + argList.String() + "); END;"
+	}
+
+	logger := getLogger(ctx)
+	if logger != nil {
+		logger.Debug("Call", "qry", qry, "params", params)
+	}
+	if _, err = ex.ExecContext(ctx, qry, params...); err != nil {
+		closeAll()
+		return nil, fmt.Errorf("%s: %w", qry, err)
+	}
+	for k, dest := range outputs {
+		outputs[k] = derefCallOutput(dest)
+	}
+	// close the converted IN objects, the OUT ones are returned
+	for _, O := range toClose {
+		var isOut bool
+		for _, v := range outputs {
+			if isOut = v == interface{}(O); isOut {
+				break
+			}
+		}
+		if !isOut {
+			O.Close()
+		}
+	}
+	return outputs, nil
+}
+
+// programArgument is a row of ALL_ARGUMENTS.
+type programArgument struct {
+	Name, DataType                   string
+	TypeOwner, TypeName, TypeSubname string
+	Overload                         string
+	Position                         int
+	IsIn, IsOut, Defaulted           bool
+}
+
+func (arg programArgument) isObject() bool {
+	switch arg.DataType {
+	case "OBJECT", "TABLE", "VARRAY", "PL/SQL TABLE", "PL/SQL RECORD", "PL/SQL COLLECTION":
+		return true
+	}
+	return false
+}
+
+// typeName returns the full name of the argument's type, as GetObjectType needs it.
+func (arg programArgument) typeName() string {
+	nm := arg.TypeOwner + "." + arg.TypeName
+	if arg.TypeSubname != "" {
+		nm += "." + arg.TypeSubname
+	}
+	return nm
+}
+
+// outDest returns a pointer for the OUT argument.
+func (arg programArgument) outDest() (interface{}, error) {
+	switch dt := arg.DataType; dt {
+	case "VARCHAR2", "VARCHAR", "CHAR", "NVARCHAR2", "NCHAR", "LONG", "ROWID", "UROWID", "CLOB", "NCLOB":
+		return new(string), nil
+	case "NUMBER", "FLOAT", "INTEGER", "BINARY_INTEGER", "PLS_INTEGER":
+		return new(Number), nil
+	case "BINARY_FLOAT", "BINARY_DOUBLE":
+		return new(float64), nil
+	case "RAW", "LONG RAW", "BLOB":
+		return new([]byte), nil
+	case "PL/SQL BOOLEAN", "BOOLEAN":
+		return new(bool), nil
+	case "REF CURSOR":
+		return new(driver.Rows), nil
+	case "INTERVAL DAY TO SECOND":
+		return new(time.Duration), nil
+	default:
+		if dt == "DATE" || strings.HasPrefix(dt, "TIMESTAMP") {
+			return new(NullTime), nil
+		}
+	}
+	return nil, fmt.Errorf("%s: unsupported OUT data type %q", arg.Name, arg.DataType)
+}
+
+// callObject converts the value to a new *Object of the argument's type (an empty one for nil),
+// or returns nil if no conversion is needed (for *Objects and structs with godror tags).
+func callObject(ctx context.Context, ex Execer, arg programArgument, value interface{}) (*Object, error) {
+	if cf, ok := value.(callField); ok {
+		rv := reflect.ValueOf(cf.Ptr).Elem()
+		if rv.Kind() == reflect.Struct {
+			return nil, nil
+		}
+		if (rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil() {
+			value = nil
+		} else {
+			value = rv.Interface()
+		}
+	}
+	switch value.(type) {
+	case *Object, Object, userType:
+		return nil, nil
+	}
+	if value != nil {
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Struct ||
+			rv.Kind() == reflect.Ptr && rv.Elem().Kind() == reflect.Struct {
+			return nil, nil
+		}
+	}
+	ot, err := GetObjectType(ctx, ex, arg.typeName())
+	if err != nil {
+		return nil, err
+	}
+	O, err := ot.NewObject()
+	if err != nil {
+		return nil, err
+	}
+	switch x := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		err = O.FromMap(true, x)
+	default:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice || ot.CollectionOf == nil {
+			err = fmt.Errorf("cannot convert %T to %s", value, ot.FullName())
+			break
+		}
+		coll := ObjectCollection{Object: O}
+		for i, n := 0, rv.Len(); i < n && err == nil; i++ {
+			elt := rv.Index(i).Interface()
+			if m, ok := elt.(map[string]interface{}); ok {
+				err = coll.FromMapSlice(true, []map[string]interface{}{m})
+			} else {
+				err = coll.Append(elt)
+			}
+		}
+	}
+	if err != nil {
+		O.Close()
+		return nil, err
+	}
+	return O, nil
+}
+
+// callField is a pointer to a struct field, for OUT arguments.
+type callField struct{ Ptr interface{} }
+
+// callArgs returns the given arguments, keyed by the uppercased name.
+// For a pointer to a struct, the values are pointers to the fields (as callField).
+func callArgs(args interface{}) (map[string]interface{}, error) {
+	given := make(map[string]interface{})
+	if args == nil {
+		return given, nil
+	}
+	if m, ok := args.(map[string]interface{}); ok {
+		for k, v := range m {
+			given[strings.ToUpper(k)] = v
+		}
+		return given, nil
+	}
+	rv := reflect.ValueOf(args)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("args must be map[string]interface{} or a pointer to a struct, not %T", args)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+		name := f.Name
+		if tag, _, _ := stringsCut(f.Tag.Get("godror"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		if O, ok := rv.Field(i).Interface().(*Object); ok {
+			given[strings.ToUpper(name)] = O
+			continue
+		}
+		given[strings.ToUpper(name)] = callField{Ptr: rv.Field(i).Addr().Interface()}
+	}
+	return given, nil
+}
+
+// derefCallOutput returns the value of the OUT argument's destination.
+func derefCallOutput(dest interface{}) interface{} {
+	switch x := dest.(type) {
+	case *Object:
+		return x
+	case *Number:
+		if *x == "" {
+			return nil
+		}
+		return *x
+	case *NullTime:
+		if !x.Valid {
+			return nil
+		}
+		return x.Time
+	case *driver.Rows:
+		return *x
+	}
+	if rv := reflect.ValueOf(dest); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		return rv.Elem().Interface()
+	}
+	return dest
+}
+
+// quoteIdent returns the identifier quoted, iff it is not a simple uppercase identifier.
+func quoteIdent(s string) string {
+	for i, c := range []byte(s) {
+		if !('A' <= c && c <= 'Z' || c == '_' || c == '$' || c == '#' || i != 0 && '0' <= c && c <= '9') {
+			return `"` + s + `"`
+		}
+	}
+	return s
+}
+
+// describeProgram returns the arguments of the stored procedure/function from ALL_ARGUMENTS,
+// grouped by overload.
+func describeProgram(ctx context.Context, ex ExecQuerier, name string) ([][]programArgument, error) {
+	const resolveQry = `BEGIN DBMS_UTILITY.NAME_RESOLVE(:1, 1, :2, :3, :4, :5, :6, :7); END;`
+	var schema, part1, part2, dblink sql.NullString
+	var part1Type, objNo int64
+	if _, err := ex.ExecContext(ctx, resolveQry, name,
+		sql.Out{Dest: &schema}, sql.Out{Dest: &part1}, sql.Out{Dest: &part2}, sql.Out{Dest: &dblink},
+		sql.Out{Dest: &part1Type}, sql.Out{Dest: &objNo},
+	); err != nil {
+		return nil, fmt.Errorf("%s [%q]: %w", resolveQry, name, err)
+	}
+	if dblink.String != "" {
+		return nil, fmt.Errorf("%s: remote (@%s) programs are not supported", name, dblink.String)
+	}
+	objName := part2.String
+	if objName == "" {
+		objName = part1.String
+	}
+
+	const qry = `SELECT overload, position, argument_name, data_type, in_out,
+       type_owner, type_name, type_subname, defaulted
+  FROM all_arguments
+  WHERE object_id = :1 AND object_name = :2 AND data_level = 0
+  ORDER BY overload NULLS FIRST, sequence`
+	rows, err := ex.QueryContext(ctx, qry, objNo, objName)
+	if err != nil {
+		return nil, fmt.Errorf("%s [%d, %q]: %w", qry, objNo, objName, err)
+	}
+	defer rows.Close()
+	var overloads [][]programArgument
+	var prevOverload string
+	for rows.Next() {
+		var overload, argName, dataType, inOut, typeOwner, typeName, typeSubname, defaulted sql.NullString
+		var arg programArgument
+		if err = rows.Scan(&overload, &arg.Position, &argName, &dataType, &inOut,
+			&typeOwner, &typeName, &typeSubname, &defaulted,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", qry, err)
+		}
+		if len(overloads) == 0 || overload.String != prevOverload {
+			overloads = append(overloads, nil)
+			prevOverload = overload.String
+		}
+		if dataType.String == "" { // no arguments
+			continue
+		}
+		arg.Overload, arg.Name, arg.DataType = overload.String, argName.String, dataType.String
+		arg.TypeOwner, arg.TypeName, arg.TypeSubname = typeOwner.String, typeName.String, typeSubname.String
+		arg.IsIn, arg.IsOut = inOut.String != "OUT", inOut.String != "IN" || arg.Position == 0
+		arg.Defaulted = defaulted.String == "Y"
+		overloads[len(overloads)-1] = append(overloads[len(overloads)-1], arg)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", qry, err)
+	}
+	if len(overloads) == 0 {
+		return nil, fmt.Errorf("%s: no arguments found in ALL_ARGUMENTS", name)
+	}
+	return overloads, nil
+}
+
+// selectOverload returns the first overload whose arguments match the given names.
+func selectOverload(overloads [][]programArgument, given map[string]interface{}) ([]programArgument, error) {
+Overloads:
+	for _, args := range overloads {
+		names := make(map[string]struct{}, len(args))
+		for _, arg := range args {
+			if arg.Position == 0 {
+				names[CallReturn] = struct{}{}
+				continue
+			}
+			nm := strings.ToUpper(arg.Name)
+			names[nm] = struct{}{}
+			if _, ok := given[nm]; !ok && arg.IsIn && !arg.Defaulted {
+				continue Overloads
+			}
+		}
+		for k := range given {
+			if _, ok := names[k]; !ok {
+				continue Overloads
+			}
+		}
+		return args, nil
+	}
+	return nil, ErrNoMatchingOverload
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectOverload(t *testing.T) {
+	overloads := [][]programArgument{
+		{
+			{Name: "P_ID", Position: 1, IsIn: true},
+			{Name: "P_NAME", Position: 2, IsIn: true, Defaulted: true},
+		},
+		{
+			{Name: "P_TXT", Position: 1, IsIn: true},
+			{Name: "P_OUT", Position: 2, IsOut: true},
+		},
+	}
+	for tN, tC := range []struct {
+		Given map[string]interface{}
+		Want  int
+	}{
+		{Given: map[string]interface{}{"P_ID": 1}, Want: 0},
+		{Given: map[string]interface{}{"P_ID": 1, "P_NAME": "a"}, Want: 0},
+		{Given: map[string]interface{}{"P_TXT": "a"}, Want: 1},
+		{Given: map[string]interface{}{"P_TXT": "a", "P_OUT": nil}, Want: 1},
+		{Given: map[string]interface{}{"P_NAME": "a"}, Want: -1},
+		{Given: map[string]interface{}{"P_ID": 1, "P_TXT": "a"}, Want: -1},
+	} {
+		got, err := selectOverload(overloads, tC.Given)
+		if tC.Want < 0 {
+			if !errors.Is(err, ErrNoMatchingOverload) {
+				t.Errorf("%d. wanted ErrNoMatchingOverload, got %v (%+v)", tN, err, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d. %+v", tN, err)
+		} else if got[0].Name != overloads[tC.Want][0].Name {
+			t.Errorf("%d. got %+v, wanted %d. overload", tN, got, tC.Want)
+		}
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	for in, want := range map[string]string{
+		"P_ID": "P_ID", "P$X#1": "P$X#1", "p_id": `"p_id"`, "1X": `"1X"`,
+	} {
+		if got := quoteIdent(in); got != want {
+			t.Errorf("%q: got %q, wanted %q", in, got, want)
+		}
+	}
+}
+
+func TestCallArgs(t *testing.T) {
+	var args struct {
+		ID       int
+		Name     string `godror:"p_name"`
+		Ignored  string `godror:"-"`
+		internal int
+	}
+	args.internal = 1
+	given, err := callArgs(&args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(given) != 2 {
+		t.Errorf("got %v, wanted ID and P_NAME", given)
+	}
+	for _, k := range []string{"ID", "P_NAME"} {
+		if _, ok := given[k]; !ok {
+			t.Errorf("%s is missing from %v", k, given)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include "dpiImpl.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unsafe"
+)
+
+// ColumnKind is the kind of the values of a ColumnVector.
+type ColumnKind uint8
+
+const (
+	// ColumnInt64 values are in ColumnVector.Int64.
+	ColumnInt64 = ColumnKind(iota + 1)
+	// ColumnUint64 values are in ColumnVector.Uint64.
+	ColumnUint64
+	// ColumnFloat64 values are in ColumnVector.Float64.
+	ColumnFloat64
+	// ColumnNumber values are decimal numbers as text (as Number), in ColumnVector.Data.
+	ColumnNumber
+	// ColumnString values are in ColumnVector.Data.
+	ColumnString
+	// ColumnBytes values are in ColumnVector.Data.
+	ColumnBytes
+	// ColumnTime values are in ColumnVector.Time.
+	ColumnTime
+	// ColumnDuration values are in ColumnVector.Int64, as nanoseconds.
+	ColumnDuration
+	// ColumnBool values are in ColumnVector.Bool.
+	ColumnBool
+)
+
+// ColumnVector holds the values of one column of a ColumnBatch.
+//
+// Only the slice of the column's Kind is filled.
+type ColumnVector struct {
+	Name string
+	// Null[i] reports whether the i-th value is NULL.
+	Null    []bool
+	Int64   []int64
+	Uint64  []uint64
+	Float64 []float64
+	Bool    []bool
+	Time    []time.Time
+	// Offsets and Data holds the variable length (ColumnNumber, ColumnString and ColumnBytes) values,
+	// the i-th value is Data[Offsets[i]:Offsets[i+1]], as in Apache Arrow.
+	Offsets []int32
+	Data    []byte
+	Kind    ColumnKind
+}
+
+// Bytes returns the i-th variable length value. The returned slice is valid only till the next batch.
+func (v *ColumnVector) Bytes(i int) []byte { return v.Data[v.Offsets[i]:v.Offsets[i+1]] }
+
+// Value returns the i-th value as an interface{} (nil for NULL).
+func (v *ColumnVector) Value(i int) interface{} {
+	if v.Null[i] {
+		return nil
+	}
+	switch v.Kind {
+	case ColumnInt64:
+		return v.Int64[i]
+	case ColumnUint64:
+		return v.Uint64[i]
+	case ColumnFloat64:
+		return v.Float64[i]
+	case ColumnNumber:
+		return Number(v.Bytes(i))
+	case ColumnString:
+		return string(v.Bytes(i))
+	case ColumnBytes:
+		return append([]byte(nil), v.Bytes(i)...)
+	case ColumnTime:
+		return v.Time[i]
+	case ColumnDuration:
+		return time.Duration(v.Int64[i])
+	case ColumnBool:
+		return v.Bool[i]
+	default:
+		return nil
+	}
+}
+
+func (v *ColumnVector) reset() {
+	v.Null, v.Int64, v.Uint64, v.Float64 = v.Null[:0], v.Int64[:0], v.Uint64[:0], v.Float64[:0]
+	v.Bool, v.Time, v.Data = v.Bool[:0], v.Time[:0], v.Data[:0]
+	v.Offsets = append(v.Offsets[:0], 0)
+}
+
+func (v *ColumnVector) appendBytes(p []byte) {
+	v.Data = append(v.Data, p...)
+	v.Offsets = append(v.Offsets, int32(len(v.Data)))
+}
+
+// ColumnBatch is a batch of rows in columnar form.
+type ColumnBatch struct {
+	Columns []ColumnVector
+	// Len is the number of rows in the batch.
+	Len int
+}
+
+// QueryColumnar executes the query, and calls f with each fetched batch of rows in columnar form,
+// built directly from the fetch buffers, without converting each value to a driver.Value.
+//
+// The size of the batches is the fetch array size (see FetchArraySize).
+// The batch, with all its slices, is reused, so it is valid only during the call of f.
+//
+// NUMBER columns are returned as ColumnInt64 or ColumnNumber (decimal text, as Number),
+// BINARY_FLOAT and BINARY_DOUBLE as ColumnFloat64, DATE and TIMESTAMP as ColumnTime,
+// (N)VARCHAR2, (N)CHAR and ROWID as ColumnString, RAW as ColumnBytes.
+// LOB, object and cursor columns are not supported.
+//
+// The args may contain Options, such as FetchArraySize.
+func QueryColumnar(ctx context.Context, ex Execer, qry string, f func(*ColumnBatch) error, args ...interface{}) error {
+	return Raw(ctx, ex, func(c Conn) error {
+		dst, err := c.PrepareContext(ctx, qry)
+		if err != nil {
+			return fmt.Errorf("%s: %w", qry, err)
+		}
+		st := dst.(*statement)
+		defer st.Close()
+		nvs := make([]driver.NamedValue, 0, len(args))
+		for _, a := range args {
+			switch x := a.(type) {
+			case Option:
+				x(&st.stmtOptions)
+			case sql.NamedArg:
+				nvs = append(nvs, driver.NamedValue{Name: x.Name, Ordinal: len(nvs) + 1, Value: x.Value})
+			default:
+				nvs = append(nvs, driver.NamedValue{Ordinal: len(nvs) + 1, Value: a})
+			}
+		}
+		dr, err := st.QueryContext(ctx, nvs)
+		if err != nil {
+			return fmt.Errorf("%s: %w", qry, err)
+		}
+		r, ok := dr.(*rows)
+		if !ok {
+			dr.Close()
+			return fmt.Errorf("%s: %T is not a query", qry, dr)
+		}
+		defer r.Close()
+
+		batch, err := r.newColumnBatch()
+		if err != nil {
+			return fmt.Errorf("%s: %w", qry, err)
+		}
+		for {
+			if err = r.nextColumnar(ctx, batch); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			if err = f(batch); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// newColumnBatch returns a ColumnBatch for the columns, or an error for an unsupported column type.
+func (r *rows) newColumnBatch() (*ColumnBatch, error) {
+	b := ColumnBatch{Columns: make([]ColumnVector, len(r.columns))}
+	for i, col := range r.columns {
+		v := &b.Columns[i]
+		v.Name = col.Name
+		switch col.OracleType {
+		case C.DPI_ORACLE_TYPE_VARCHAR, C.DPI_ORACLE_TYPE_NVARCHAR,
+			C.DPI_ORACLE_TYPE_CHAR, C.DPI_ORACLE_TYPE_NCHAR,
+			C.DPI_ORACLE_TYPE_LONG_VARCHAR, C.DPI_ORACLE_TYPE_LONG_NVARCHAR,
+			C.DPI_ORACLE_TYPE_ROWID:
+			v.Kind = ColumnString
+		case C.DPI_ORACLE_TYPE_NUMBER:
+			switch col.NativeType {
+			case C.DPI_NATIVE_TYPE_INT64:
+				v.Kind = ColumnInt64
+			case C.DPI_NATIVE_TYPE_UINT64:
+				v.Kind = ColumnUint64
+			case C.DPI_NATIVE_TYPE_BYTES:
+				v.Kind = ColumnNumber
+			}
+		case C.DPI_ORACLE_TYPE_NATIVE_INT:
+			v.Kind = ColumnInt64
+		case C.DPI_ORACLE_TYPE_NATIVE_UINT:
+			v.Kind = ColumnUint64
+		case C.DPI_ORACLE_TYPE_NATIVE_FLOAT, C.DPI_ORACLE_TYPE_NATIVE_DOUBLE:
+			v.Kind = ColumnFloat64
+		case C.DPI_ORACLE_TYPE_RAW, C.DPI_ORACLE_TYPE_LONG_RAW:
+			v.Kind = ColumnBytes
+		case C.DPI_ORACLE_TYPE_DATE, C.DPI_ORACLE_TYPE_TIMESTAMP,
+			C.DPI_ORACLE_TYPE_TIMESTAMP_TZ, C.DPI_ORACLE_TYPE_TIMESTAMP_LTZ:
+			v.Kind = ColumnTime
+		case C.DPI_ORACLE_TYPE_INTERVAL_DS:
+			v.Kind = ColumnDuration
+		case C.DPI_ORACLE_TYPE_BOOLEAN:
+			v.Kind = ColumnBool
+		}
+		if v.Kind == 0 {
+			return nil, fmt.Errorf("column %q: type %s is not supported", col.Name, r.ColumnTypeDatabaseTypeName(i))
+		}
+	}
+	return &b, nil
+}
+
+// nextColumnar fills b with the next batch of rows, returning io.EOF at the end.
+func (r *rows) nextColumnar(ctx context.Context, b *ColumnBatch) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.fetched == 0 {
+		if err := r.fetch(ctx, getLogger(ctx)); err != nil {
+			return err
+		}
+	}
+	start, n := int(r.bufferRowIndex), int(r.fetched)
+	tz := r.conn.Timezone()
+	b.Len = n
+	for i, col := range r.columns {
+		v := &b.Columns[i]
+		v.reset()
+		for _, d := range r.data[i][start : start+n] {
+			isNull := d.isNull == 1
+			v.Null = append(v.Null, isNull)
+			switch v.Kind {
+			case ColumnInt64:
+				var i64 int64
+				if !isNull {
+					i64 = *((*int64)(unsafe.Pointer(&d.value)))
+				}
+				v.Int64 = append(v.Int64, i64)
+			case ColumnUint64:
+				var u64 uint64
+				if !isNull {
+					u64 = *((*uint64)(unsafe.Pointer(&d.value)))
+				}
+				v.Uint64 = append(v.Uint64, u64)
+			case ColumnFloat64:
+				var f64 float64
+				if !isNull {
+					if col.OracleType == C.DPI_ORACLE_TYPE_NATIVE_FLOAT {
+						f64 = float64(*((*float32)(unsafe.Pointer(&d.value))))
+					} else {
+						f64 = *((*float64)(unsafe.Pointer(&d.value)))
+					}
+				}
+				v.Float64 = append(v.Float64, f64)
+			case ColumnNumber, ColumnString, ColumnBytes:
+				if isNull {
+					v.appendBytes(nil)
+					continue
+				}
+				if col.OracleType == C.DPI_ORACLE_TYPE_ROWID {
+					cRowid := *((**C.dpiRowid)(unsafe.Pointer(&d.value)))
+					var cBuf *C.char
+					var cLen C.uint32_t
+					if err := r.statement.checkExecNoLOT(func() C.int {
+						return C.dpiRowid_getStringValue(cRowid, &cBuf, &cLen)
+					}); err != nil {
+						return err
+					}
+					v.appendBytes(unsafe.Slice((*byte)(unsafe.Pointer(cBuf)), cLen))
+					continue
+				}
+				p := (*C.dpiBytes)(unsafe.Pointer(&d.value))
+				v.appendBytes(unsafe.Slice((*byte)(unsafe.Pointer(p.ptr)), p.length))
+			case ColumnTime:
+				var t time.Time
+				if !isNull {
+					ts := *((*C.dpiTimestamp)(unsafe.Pointer(&d.value)))
+					loc := tz
+					if col.OracleType == C.DPI_ORACLE_TYPE_TIMESTAMP_TZ || col.OracleType == C.DPI_ORACLE_TYPE_TIMESTAMP_LTZ {
+						loc = timeZoneFor(ts.tzHourOffset, ts.tzMinuteOffset, nil)
+					}
+					if loc == nil {
+						loc = time.Local
+					}
+					t = time.Date(
+						int(ts.year), time.Month(ts.month), int(ts.day),
+						int(ts.hour), int(ts.minute), int(ts.second), int(ts.fsecond),
+						loc,
+					)
+				}
+				v.Time = append(v.Time, t)
+			case ColumnDuration:
+				var dur time.Duration
+				if !isNull {
+					dataGetIntervalDS(ctx, &dur, &d)
+				}
+				v.Int64 = append(v.Int64, int64(dur))
+			case ColumnBool:
+				v.Bool = append(v.Bool, !isNull && C.dpiData_getBool(&d) != 0)
+			}
+		}
+	}
+	r.bufferRowIndex += r.fetched
+	r.fetched = 0
+	return nil
+}
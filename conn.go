@@ -57,8 +57,10 @@ type conn struct {
 	drv                 *drv
 	dpiConn             *C.dpiConn
 	currentTT           atomic.Value
+	tranCtx             context.Context // context of BeginTx, for tracing
 	tranParams          tranParams
 	poolKey             string
+	retag               string // the tag of the session to be set on release
 	Edition, DomainName string
 	DBName, ServiceName string
 	Server              VersionInfo
@@ -66,6 +68,7 @@ type conn struct {
 	mu                  sync.RWMutex
 	objTypes            map[string]*ObjectType
 	tzOffSecs           int
+	lobPrefetchSize     int
 	inTransaction       bool
 	released            bool
 	tzValid             bool
@@ -229,6 +232,7 @@ func (c *conn) closeNotLocking() error {
 	if c == nil {
 		return nil
 	}
+	currentTT, _ := c.currentTT.Load().(TraceTag)
 	c.currentTT.Store(TraceTag{})
 	dpiConn := c.dpiConn
 	if dpiConn == nil {
@@ -237,11 +241,32 @@ func (c *conn) closeNotLocking() error {
 	c.dpiConn = nil
 	if dpiConn.refCount <= 1 {
 		c.tzOffSecs, c.tzValid, c.params.Timezone = 0, false, nil
+		if currentTT.ECID != "" {
+			// Do not let the next user of the pooled session inherit the ECID.
+			_ = C.dpiConn_setEcontextId(dpiConn, nil, 0)
+		}
 	}
 	for k, v := range c.objTypes {
 		_ = v.Close()
 		delete(c.objTypes, k)
 	}
+	if tag := c.retag; tag != "" {
+		c.retag = ""
+		// Retag the session while releasing it to the pool, iff this is the last reference.
+		// This fails if statements or LOBs are still open - then it keeps its old tag.
+		if dpiConn.refCount <= 1 {
+			cTag := C.CString(tag)
+			err := c.drv.checkExec(func() C.int {
+				return C.dpiConn_close(dpiConn, C.DPI_MODE_CONN_CLOSE_RETAG, cTag, C.uint32_t(len(tag)))
+			})
+			C.free(unsafe.Pointer(cTag))
+			if err != nil {
+				if logger := c.getLogger(context.TODO()); logger != nil {
+					logger.Warn("retag session", "tag", tag, "error", err)
+				}
+			}
+		}
+	}
 
 	// dpiConn_release decrements dpiConn's reference counting,
 	// and closes it when it reaches zero.
@@ -324,7 +349,8 @@ func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, e
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.inTransaction = true
-	if tt, ok := ctx.Value(traceTagCtxKey{}).(TraceTag); ok {
+	c.tranCtx = ctx
+	if tt, ok := c.traceTagFromContext(ctx); ok {
 		_ = c.setTraceTag(tt)
 	}
 	return c, nil
@@ -342,7 +368,7 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, e
 		return nil, err
 	}
 
-	if tt, ok := ctx.Value(traceTagCtxKey{}).(TraceTag); ok {
+	if tt, ok := c.traceTagFromContext(ctx); ok {
 		_ = c.setTraceTag(tt)
 	}
 	// TODO: get rid of this hack
@@ -354,9 +380,23 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, e
 		return &statement{conn: c, query: query}, nil
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.prepareContextNotLocked(ctx, query)
+	call := InterceptedCall{Operation: "Prepare", SQL: query}
+	err := c.intercept(ctx, &call, func(ctx context.Context) error {
+		ctx, ts := c.startSpan(ctx, "Prepare", call.SQL)
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		var err error
+		call.Stmt, err = c.prepareContextNotLocked(ctx, call.SQL)
+		ts.end(err)
+		return err
+	})
+	if err == nil && call.Stmt == nil {
+		err = errNoInterceptedResult
+	}
+	if err != nil {
+		return nil, err
+	}
+	return call.Stmt, nil
 }
 func (c *conn) prepareContextNotLocked(ctx context.Context, query string) (driver.Stmt, error) {
 	if err := ctx.Err(); err != nil {
@@ -390,10 +430,29 @@ func (c *conn) Rollback() error {
 	return c.endTran(false)
 }
 func (c *conn) endTran(isCommit bool) error {
+	operation := "Rollback"
+	if isCommit {
+		operation = "Commit"
+	}
+	c.mu.RLock()
+	ctx := c.tranCtx
+	c.mu.RUnlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	call := InterceptedCall{Operation: operation}
+	return c.intercept(ctx, &call, func(ctx context.Context) error {
+		return c.endTranNotIntercepted(ctx, isCommit, operation)
+	})
+}
+
+func (c *conn) endTranNotIntercepted(ctx context.Context, isCommit bool, operation string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.inTransaction = false
 	c.tranParams = tranParams{}
+	_, ts := startSpan(ctx, operation, "")
+	c.tranCtx = nil
 
 	var err error
 	//msg := "Commit"
@@ -408,6 +467,7 @@ func (c *conn) endTran(isCommit bool) error {
 		}
 	}
 	//fmt.Printf("%p.%s\n", c, msg)
+	ts.end(err)
 	return err
 }
 
@@ -451,6 +511,24 @@ func (c *conn) newVar(vi varInfo) (*C.dpiVar, []C.dpiData, error) {
 
 var _ = driver.Tx((*conn)(nil))
 
+// ociAttrDefaultLobPrefetchSize is OCI_ATTR_DEFAULT_LOBPREFETCH_SIZE, the default LOB prefetch size of the session.
+const ociAttrDefaultLobPrefetchSize = 438
+
+// setLobPrefetchSize sets the amount of LOB data prefetched with the LOB locators.
+func (c *conn) setLobPrefetchSize(size int) error {
+	if c == nil || c.lobPrefetchSize == size {
+		return nil
+	}
+	v := C.uint32_t(size)
+	if err := c.checkExec(func() C.int {
+		return C.dpiConn_setOciAttr(c.dpiConn, C.DPI_OCI_HTYPE_SESSION, ociAttrDefaultLobPrefetchSize, unsafe.Pointer(&v), C.uint32_t(unsafe.Sizeof(v)))
+	}); err != nil {
+		return fmt.Errorf("set LOB prefetch size to %d: %w", size, err)
+	}
+	c.lobPrefetchSize = size
+	return nil
+}
+
 func (c *conn) ServerVersion() (VersionInfo, error) {
 	if c.Server.Version != 0 {
 		return c.Server, nil
@@ -782,62 +860,17 @@ func IsBadConn(err error) bool {
 	if !errors.As(err, &cd) {
 		return false
 	}
-	// Yes, this is copied from rana/ora, but I've put it there, so it's mine. @tgulacsi
-	switch cd.Code() {
-	case 0:
-		if strings.Contains(err.Error(), " DPI-1002: ") {
-			return true
-		}
-
-	case // cases by experience:
-		3106,  // fatal two-task communication protocol error
-		12170, // TNS:Connect timeout occurred
-		12528, // TNS:listener: all appropriate instances are blocking new connections
-		12545: // Connect failed because target host or object does not exist
-		fallthrough
-	case // cases from go-oci8
-		1033, // ORACLE initialization or shutdown in progress
-		1034: // ORACLE not available
-		fallthrough
-	case //cases from https://github.com/oracle/odpi/blob/master/src/dpiError.c#L61-L94
-		22,    // invalid session ID; access denied
-		28,    // your session has been killed
-		31,    // your session has been marked for kill
-		45,    // your session has been terminated with no replay
-		378,   // buffer pools cannot be created as specified
-		602,   // internal programming exception
-		603,   // ORACLE server session terminated by fatal error
-		609,   // could not attach to incoming connection
-		1012,  // not logged on
-		1041,  // internal error. hostdef extension doesn't exist
-		1043,  // user side memory corruption
-		1089,  // immediate shutdown or close in progress
-		1092,  // ORACLE instance terminated. Disconnection forced
-		2396,  // exceeded maximum idle time, please connect again
-		3113,  // end-of-file on communication channel
-		3114,  // not connected to ORACLE
-		3122,  // attempt to close ORACLE-side window on user side
-		3135,  // connection lost contact
-		3136,  // inbound connection timed out
-		12153, // TNS:not connected
-		12537, // TNS:connection closed
-		12547, // TNS:lost contact
-		12570, // TNS:packet reader failure
-		12583, // TNS:no reader
-		27146, // post/wait initialization failed
-		28511, // lost RPC connection
-		28547, // connection to server failed, probable Oracle Net admin error
-		56600: // an illegal OCI function call was issued
-		return true
+	if cd.Code() == 0 {
+		return strings.Contains(err.Error(), " DPI-1002: ")
 	}
-	return false
+	return oraErrCodes[cd.Code()].class == ClassBadConn
 }
 
 func (c *conn) setTraceTag(tt TraceTag) error {
 	if c == nil || c.dpiConn == nil {
 		return nil
 	}
-	todo := make([][2]string, 0, 5)
+	todo := make([][2]string, 0, 6)
 	currentTT, _ := c.currentTT.Load().(TraceTag)
 	for nm, vv := range map[string][2]string{
 		"action":     {currentTT.Action, tt.Action},
@@ -845,6 +878,7 @@ func (c *conn) setTraceTag(tt TraceTag) error {
 		"info":       {currentTT.ClientInfo, tt.ClientInfo},
 		"identifier": {currentTT.ClientIdentifier, tt.ClientIdentifier},
 		"op":         {currentTT.DbOp, tt.DbOp},
+		"ecid":       {currentTT.ECID, tt.ECID},
 	} {
 		if vv[0] == vv[1] {
 			continue
@@ -876,6 +910,8 @@ func (c *conn) setTraceTag(tt TraceTag) error {
 			res = C.dpiConn_setClientIdentifier(c.dpiConn, s, length)
 		case "op":
 			res = C.dpiConn_setDbOp(c.dpiConn, s, length)
+		case "ecid":
+			res = C.dpiConn_setEcontextId(c.dpiConn, s, length)
 		}
 		if s != nil {
 			C.free(unsafe.Pointer(s))
@@ -891,7 +927,7 @@ func (c *conn) GetPoolStats() (stats PoolStats, err error) {
 		return stats, nil
 	}
 	c.mu.RLock()
-	key, drv := c.poolKey, c.drv
+	key, drv, class := c.poolKey, c.drv, c.params.ConnClass
 	c.mu.RUnlock()
 	if key == "" {
 		// not pooled connection
@@ -904,7 +940,17 @@ func (c *conn) GetPoolStats() (stats PoolStats, err error) {
 	if pool == nil {
 		return stats, nil
 	}
-	return drv.getPoolStats(pool)
+	if stats, err = drv.getPoolStats(pool); err != nil || class == "" {
+		return stats, err
+	}
+	if s, ok, err := c.drcpClassStats(class); err != nil {
+		if logger := c.getLogger(context.TODO()); logger != nil {
+			logger.Warn("DRCP statistics", "class", class, "error", err)
+		}
+	} else if ok {
+		stats.DRCPClass, stats.DRCP = class, s
+	}
+	return stats, nil
 }
 
 type traceTagCtxKey struct{}
@@ -915,6 +961,23 @@ func ContextWithTraceTag(ctx context.Context, tt TraceTag) context.Context {
 	return context.WithValue(ctx, traceTagCtxKey{}, tt)
 }
 
+// traceTagFromContext returns the TraceTag of the context,
+// with the ECID derived from the W3C traceparent of the context, if not set.
+//
+// Without a TraceTag in the context, only the ECID of the session's current TraceTag is replaced.
+func (c *conn) traceTagFromContext(ctx context.Context) (TraceTag, bool) {
+	tt, ok := ctx.Value(traceTagCtxKey{}).(TraceTag)
+	if tt.ECID == "" {
+		if tp, tpOK := ctx.Value(traceparentCtxKey{}).(traceparent); tpOK {
+			if !ok {
+				tt, _ = c.currentTT.Load().(TraceTag)
+			}
+			tt.ECID, ok = tp.TraceID, true
+		}
+	}
+	return tt, ok
+}
+
 // TraceTag holds tracing information for the session. It can be set on the session
 // with ContextWithTraceTag.
 type TraceTag struct {
@@ -928,10 +991,13 @@ type TraceTag struct {
 	Module string
 	// Action - specifies an action, such as an INSERT or UPDATE operation, in a module
 	Action string
+	// ECID - execution context id, an end-to-end request id (such as the trace-id of a W3C traceparent),
+	// shown in V$SESSION.ECID, audit trails and SQL Monitor reports
+	ECID string
 }
 
 func (tt TraceTag) String() string {
-	q := make(url.Values, 5)
+	q := make(url.Values, 6)
 	if tt.ClientIdentifier != "" {
 		q.Add("clientIdentifier", tt.ClientIdentifier)
 	}
@@ -947,6 +1013,9 @@ func (tt TraceTag) String() string {
 	if tt.Action != "" {
 		q.Add("action", tt.Action)
 	}
+	if tt.ECID != "" {
+		q.Add("ecid", tt.ECID)
+	}
 	return q.Encode()
 }
 
@@ -1060,7 +1129,7 @@ var _ = driver.SessionResetter((*conn)(nil))
 // if the connection has been used before. If the driver returns driver.ErrBadConn
 // the connection is discarded.
 //
-// This implementation does nothing if the connection is not pooled,
+// This implementation only clears the ECID if the connection is not pooled,
 // but reacquires a new session if it is pooled.
 //
 // This ensures that the session is not stale.
@@ -1079,6 +1148,16 @@ func (c *conn) ResetSession(ctx context.Context) error {
 		if !dpiConnOK {
 			return driver.ErrBadConn
 		}
+		if tt, _ := c.currentTT.Load().(TraceTag); tt.ECID != "" {
+			// Do not let the next request inherit the ECID of the previous one.
+			tt.ECID = ""
+			c.mu.Lock()
+			err := c.setTraceTag(tt)
+			c.mu.Unlock()
+			if err != nil {
+				return fmt.Errorf("%v: %w", err, driver.ErrBadConn)
+			}
+		}
 		return nil
 	}
 	// FIXME(tgulacsi): Prepared statements hold the previous session,
@@ -1098,6 +1177,7 @@ func (c *conn) ResetSession(ctx context.Context) error {
 		return nil
 	}
 	P := commonAndConnParams{CommonParams: params.CommonParams, ConnParams: params.ConnParams}
+	tag := sessionTagFromContext(ctx)
 	var paramsFromCtx bool
 	if ctxValue := ctx.Value(userPasswCtxKey{}); ctxValue != nil {
 		if cc, ok := ctxValue.(commonAndConnParams); ok {
@@ -1120,6 +1200,7 @@ func (c *conn) ResetSession(ctx context.Context) error {
 			}
 		}
 	}
+	applyDRCPFromContext(ctx, &P.ConnParams)
 	if logger != nil {
 		logger.Debug("ResetSession re-acquire session", "pool", pool.key)
 	}
@@ -1129,7 +1210,10 @@ func (c *conn) ResetSession(ctx context.Context) error {
 		// Just release
 		_ = c.closeNotLocking()
 	}
+	P.tag = tag
+	_, ts := startSpan(ctx, "Connect", "")
 	dpiConn, isNew, cleanup, err := c.drv.acquireConn(pool, P)
+	ts.end(err)
 	c.mu.Unlock()
 	if err != nil {
 		return fmt.Errorf("%v: %w", err, driver.ErrBadConn)
@@ -1139,7 +1223,14 @@ func (c *conn) ResetSession(ctx context.Context) error {
 		runtime.SetFinalizer(&c, func(*conn) { cleanup() })
 	}
 
-	return c.init(ctx, isNew, P.OnInit)
+	onInit := P.OnInit
+	if tag.found() {
+		onInit = nil
+	}
+	if err = c.init(ctx, isNew, onInit); err != nil {
+		return err
+	}
+	return c.fixupSessionTag(ctx, tag)
 }
 
 // Validator may be implemented by Conn to allow drivers to
@@ -0,0 +1,126 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/godror/godror/dsn"
+)
+
+type drcpCtxKey struct{}
+
+// drcpParams are the DRCP connection class and purity requested by the context.
+type drcpParams struct {
+	ConnClass string
+	Purity    Purity
+}
+
+// ContextWithDRCP returns a context which acquires the DRCP session with the given
+// connection class and purity, overriding the connectionClass and purity of the connection parameters.
+// The empty connClass and PurityDefault leave the configured ones.
+//
+// Like ContextWithUserPassw, these are used when database/sql opens or reuses a connection,
+// so use it with *sql.Conn or *sql.Tx.
+func ContextWithDRCP(ctx context.Context, connClass string, purity Purity) context.Context {
+	return context.WithValue(ctx, drcpCtxKey{}, drcpParams{ConnClass: connClass, Purity: purity})
+}
+
+// applyDRCPFromContext overrides the connection class and purity of P, if the context sets them.
+func applyDRCPFromContext(ctx context.Context, P *dsn.ConnParams) {
+	if ctx == nil {
+		return
+	}
+	dp, ok := ctx.Value(drcpCtxKey{}).(drcpParams)
+	if !ok {
+		return
+	}
+	if dp.ConnClass != "" {
+		P.ConnClass = dp.ConnClass
+	}
+	if dp.Purity != PurityDefault {
+		P.Purity = dp.Purity
+	}
+}
+
+// DRCPStats are the DRCP statistics of a connection class, as reported by the server
+// in V$CPOOL_CC_STATS: the number of session requests, and of those
+// the ones served by a pooled session of the class (hits) and the ones which needed a new one (misses).
+type DRCPStats struct {
+	Requests, Hits, Misses uint64
+}
+
+func (s DRCPStats) String() string {
+	return fmt.Sprintf("requests=%d hits=%d misses=%d", s.Requests, s.Hits, s.Misses)
+}
+
+// drcpClassStats returns the server's DRCP statistics of the connection class,
+// as the class given, or qualified with the user name.
+// ok is false iff the server has no statistics for the class.
+func (c *conn) drcpClassStats(class string) (stats DRCPStats, ok bool, err error) {
+	const qry = `SELECT TO_CHAR(SUM(num_requests)), TO_CHAR(SUM(num_hits)), TO_CHAR(SUM(num_misses))
+  FROM v$cpool_cc_stats WHERE cclass_name IN (:1, USER||'.'||:2) HAVING COUNT(0) > 0`
+	ctx, cancel := context.WithTimeout(context.Background(), baseWaitTimeout)
+	defer cancel()
+	c.mu.RLock()
+	st, err := c.prepareContextNotLocked(ctx, qry)
+	c.mu.RUnlock()
+	if err != nil {
+		return stats, false, fmt.Errorf("%s: %w", qry, err)
+	}
+	defer st.Close()
+	rows, err := st.(*statement).queryContextNotLocked(ctx, []driver.NamedValue{
+		{Ordinal: 1, Value: class}, {Ordinal: 2, Value: class},
+	})
+	if err != nil {
+		return stats, false, fmt.Errorf("%s: %w", qry, err)
+	}
+	defer rows.Close()
+	vals := make([]driver.Value, 3)
+	if err = rows.Next(vals); err != nil {
+		if err == io.EOF {
+			return stats, false, nil
+		}
+		return stats, false, fmt.Errorf("%s: %w", qry, err)
+	}
+	for i, dest := range []*uint64{&stats.Requests, &stats.Hits, &stats.Misses} {
+		if *dest, err = strconv.ParseUint(fmt.Sprint(vals[i]), 10, 64); err != nil {
+			return stats, false, fmt.Errorf("parse %q: %w", vals[i], err)
+		}
+	}
+	return stats, true, nil
+}
+
+// DRCPClassStats returns the DRCP statistics of the server (V$CPOOL_CC_STATS),
+// summed per connection class, across the instances.
+//
+// Reading V$CPOOL_CC_STATS needs the SELECT_CATALOG_ROLE (or a grant on it).
+func DRCPClassStats(ctx context.Context, q Querier) (map[string]DRCPStats, error) {
+	const qry = `SELECT cclass_name, TO_CHAR(SUM(num_requests)), TO_CHAR(SUM(num_hits)), TO_CHAR(SUM(num_misses))
+  FROM v$cpool_cc_stats GROUP BY cclass_name`
+	rows, err := q.QueryContext(ctx, qry)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", qry, err)
+	}
+	defer rows.Close()
+	m := make(map[string]DRCPStats)
+	for rows.Next() {
+		var class string
+		var s DRCPStats
+		if err = rows.Scan(&class, &s.Requests, &s.Hits, &s.Misses); err != nil {
+			return m, fmt.Errorf("%s: %w", qry, err)
+		}
+		m[class] = s
+	}
+	if err = rows.Err(); err != nil {
+		return m, fmt.Errorf("%s: %w", qry, err)
+	}
+	return m, nil
+}
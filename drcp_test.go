@@ -0,0 +1,30 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"testing"
+
+	"github.com/godror/godror/dsn"
+)
+
+func TestApplyDRCPFromContext(t *testing.T) {
+	t.Parallel()
+	P := dsn.ConnParams{ConnClass: "A", Purity: PuritySelf}
+	applyDRCPFromContext(context.Background(), &P)
+	if P.ConnClass != "A" || P.Purity != PuritySelf {
+		t.Errorf("empty context changed the params to %+v", P)
+	}
+	applyDRCPFromContext(ContextWithDRCP(context.Background(), "", PurityDefault), &P)
+	if P.ConnClass != "A" || P.Purity != PuritySelf {
+		t.Errorf("defaults changed the params to %+v", P)
+	}
+	applyDRCPFromContext(ContextWithDRCP(context.Background(), "B", PurityNew), &P)
+	if P.ConnClass != "B" || P.Purity != PurityNew {
+		t.Errorf("got %+v, wanted B and new", P)
+	}
+}
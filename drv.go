@@ -20,6 +20,7 @@
 //	poolPingInterval=
 //	poolIncrement=1
 //	connectionClass=
+//	purity=
 //	standaloneConnection=0
 //	enableEvents=0
 //	heterogeneousPool=0
@@ -38,6 +39,8 @@
 //	stmtCacheSize=
 //	charset=UTF-8
 //	noBreakOnContextCancel=
+//	slowQueryThreshold=
+//	slowQueryPlan=0
 //
 // These are the defaults.
 // For external authentication, user and password should be empty
@@ -63,6 +66,8 @@
 // For connectionClass usage, see https://www.oracle.com/pls/topic/lookup?ctx=dblatest&id=GUID-CE6E4DCC-92DF-4946-92B8-2BDD9845DA35
 //
 // If you specify server_type as POOLED in sid, DRCP is used.
+// With DRCP, purity=new requests a fresh session, purity=self allows reusing
+// a session of the same connection class (see ContextWithDRCP for setting them per request).
 // For what can be used as "sid", see https://www.oracle.com/pls/topic/lookup?ctx=dblatest&id=GUID-E5358DEA-D619-4B7B-A799-3D2F802500F1
 //
 // Go strings are UTF-8, so the default charset should be used unless there's a really good reason to interfere with Oracle's character set conversion.
@@ -146,6 +151,13 @@ const (
 	DefaultMaxLifeTime = dsn.DefaultMaxLifeTime
 	//DefaultStandaloneConnection holds the default for standaloneConnection.
 	DefaultStandaloneConnection = dsn.DefaultStandaloneConnection
+
+	// PurityDefault is PuritySelf for pooled, PurityNew for standalone connections.
+	PurityDefault = dsn.PurityDefault
+	// PurityNew requests a new DRCP session.
+	PurityNew = dsn.PurityNew
+	// PuritySelf allows reusing a DRCP session of the same connection class.
+	PuritySelf = dsn.PuritySelf
 )
 
 // dsn is separated out for fuzzing, but keep it as "internal"
@@ -155,6 +167,9 @@ type (
 	ConnParams       = dsn.ConnParams
 	PoolParams       = dsn.PoolParams
 	Password         = dsn.Password
+	Interceptor      = dsn.Interceptor
+	InterceptedCall  = dsn.InterceptedCall
+	Purity           = dsn.Purity
 )
 
 // ParseConnString is deprecated, use ParseDSN.
@@ -227,9 +242,10 @@ type locationWithOffSecs struct {
 	offSecs int
 }
 type connPool struct {
-	dpiPool *C.dpiPool
-	key     string
-	params  commonAndPoolParams
+	dpiPool  *C.dpiPool
+	key      string
+	params   commonAndPoolParams
+	acquires acquireCounters
 }
 
 // Purge force-closes the pool's connections then closes the pool.
@@ -387,6 +403,10 @@ func (d *drv) createConn(pool *connPool, P commonAndConnParams) (*conn, bool, er
 	if err != nil {
 		return nil, false, err
 	}
+	onInit := getOnInit(&P.CommonParams)
+	if P.tag.found() {
+		onInit = nil
+	}
 	var poolKey string
 	if pool != nil {
 		poolKey = pool.key
@@ -427,7 +447,7 @@ func (d *drv) createConn(pool *connPool, P commonAndConnParams) (*conn, bool, er
 		}
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), nvlD(c.params.WaitTimeout, time.Minute))
-	err = c.init(ctx, isNew, getOnInit(&c.params.CommonParams))
+	err = c.init(ctx, isNew, onInit)
 	cancel()
 	if err != nil {
 		_ = c.closeNotLocking()
@@ -484,8 +504,11 @@ func (d *drv) acquireConn(pool *connPool, P commonAndConnParams) (*C.dpiConn, bo
 		commonCreateParamsPtr = &commonCreateParams
 	}
 	// manage strings
-	var cUsername, cPassword, cNewPassword, cConnectString, cConnClass *C.char
+	var cUsername, cPassword, cNewPassword, cConnectString, cConnClass, cTag *C.char
 	defer func() {
+		if cTag != nil {
+			C.free(unsafe.Pointer(cTag))
+		}
 		if cUsername != nil {
 			C.free(unsafe.Pointer(cUsername))
 		}
@@ -517,6 +540,13 @@ func (d *drv) acquireConn(pool *connPool, P commonAndConnParams) (*C.dpiConn, bo
 		connCreateParams.connectionClass = cConnClass
 		connCreateParams.connectionClassLength = C.uint32_t(len(P.ConnClass))
 	}
+	// assign purity
+	switch P.Purity {
+	case dsn.PurityNew:
+		connCreateParams.purity = C.DPI_PURITY_NEW
+	case dsn.PuritySelf:
+		connCreateParams.purity = C.DPI_PURITY_SELF
+	}
 
 	// assign new password (only relevant for standalone connections)
 	if pool == nil && !P.NewPassword.IsZero() {
@@ -595,6 +625,15 @@ func (d *drv) acquireConn(pool *connPool, P commonAndConnParams) (*C.dpiConn, bo
 	// if a pool was provided, assign the pool
 	if pool != nil {
 		connCreateParams.pool = pool.dpiPool
+		// request the tagged session
+		if tag := P.tag; tag != nil && tag.Requested != "" {
+			cTag = C.CString(tag.Requested)
+			connCreateParams.tag = cTag
+			connCreateParams.tagLength = C.uint32_t(len(tag.Requested))
+			if tag.MatchAny {
+				connCreateParams.matchAnyTag = 1
+			}
+		}
 	}
 
 	// setup credentials
@@ -638,6 +677,16 @@ func (d *drv) acquireConn(pool *connPool, P commonAndConnParams) (*C.dpiConn, bo
 	}
 	//use the information from ODPI driver if new connection has been created or it is only pooled
 	isNew := connCreateParams.outNewSession == 1
+	if pool != nil {
+		pool.acquires.count(isNew)
+	}
+	if tag := P.tag; tag != nil {
+		tag.Found = connCreateParams.outTagFound == 1
+		tag.Actual = ""
+		if connCreateParams.outTagLength != 0 {
+			tag.Actual = C.GoStringN(connCreateParams.outTag, C.int(connCreateParams.outTagLength))
+		}
+	}
 	return dc, isNew, cleanup, nil
 }
 
@@ -658,21 +707,26 @@ func (d *drv) createConnFromParams(ctx context.Context, P dsn.ConnectionParams)
 			return nil, err
 		}
 	}
-	conn, isNew, err := d.createConn(pool, commonAndConnParams{CommonParams: P.CommonParams, ConnParams: P.ConnParams})
+	tag := sessionTagFromContext(ctx)
+	conn, isNew, err := d.createConn(pool, commonAndConnParams{CommonParams: P.CommonParams, ConnParams: P.ConnParams, tag: tag})
 	if err != nil {
 		return conn, err
 	}
 
-	if P.CommonParams.InitOnNewConn && !isNew {
-		return conn, nil
-	}
-
 	onInit := getOnInit(&conn.params.CommonParams)
-	if onInit == nil {
-		return conn, err
+	if P.CommonParams.InitOnNewConn && !isNew || tag.found() {
+		onInit = nil
+	}
+	if onInit == nil && tag == nil {
+		return conn, nil
 	}
 	ctx, cancel := context.WithTimeout(ctx, nvlD(conn.params.WaitTimeout, time.Minute))
-	err = onInit(ctx, conn)
+	if onInit != nil {
+		err = onInit(ctx, conn)
+	}
+	if err == nil {
+		err = conn.fixupSessionTag(ctx, tag)
+	}
 	cancel()
 	if err != nil {
 		conn.Close()
@@ -866,11 +920,59 @@ func (d *drv) createPool(P commonAndPoolParams) (*connPool, error) {
 type PoolStats struct {
 	Busy, Open, Max                   uint32
 	MaxLifetime, Timeout, WaitTimeout time.Duration
+	// Acquires counts the session acquisitions from the pool.
+	Acquires AcquireStats
+	// DRCP is the server's DRCP statistics (V$CPOOL_CC_STATS) of the DRCPClass connection class.
+	//
+	// Conn.GetPoolStats fills them for the connection class (connectionClass) of the connection,
+	// if the server has statistics for it - that is, with DRCP.
+	// Reading V$CPOOL_CC_STATS needs the SELECT_CATALOG_ROLE (or a grant on it),
+	// without that the failure is logged, and DRCPClass is left empty.
+	DRCP      DRCPStats
+	DRCPClass string
 }
 
 func (s PoolStats) String() string {
-	return fmt.Sprintf("busy=%d open=%d max=%d maxLifetime=%s timeout=%s waitTimeout=%s",
-		s.Busy, s.Open, s.Max, s.MaxLifetime, s.Timeout, s.WaitTimeout)
+	str := fmt.Sprintf("busy=%d open=%d max=%d maxLifetime=%s timeout=%s waitTimeout=%s %s",
+		s.Busy, s.Open, s.Max, s.MaxLifetime, s.Timeout, s.WaitTimeout, s.Acquires)
+	if s.DRCPClass == "" {
+		return str
+	}
+	return fmt.Sprintf("%s drcp[%s]: %s", str, s.DRCPClass, s.DRCP)
+}
+
+// AcquireStats counts the sessions acquired from the (client side) session pool:
+// Reused is the number of acquisitions which got an already existing session,
+// New is the number of the ones for which a new session had to be created.
+//
+// These are counted for every pool, with or without DRCP,
+// and are not the server's DRCP statistics - for those, see PoolStats.DRCP and DRCPClassStats.
+type AcquireStats struct {
+	Requests, Reused, New uint64
+}
+
+func (s AcquireStats) String() string {
+	return fmt.Sprintf("acquires=%d reused=%d new=%d", s.Requests, s.Reused, s.New)
+}
+
+// acquireCounters count the session acquisitions of a pool.
+type acquireCounters struct {
+	requests, news atomic.Uint64
+}
+
+// count an acquisition, which got a new session iff isNew.
+func (ac *acquireCounters) count(isNew bool) {
+	ac.requests.Add(1)
+	if isNew {
+		ac.news.Add(1)
+	}
+}
+
+func (ac *acquireCounters) stats() AcquireStats {
+	// load news first, so Reused cannot underflow
+	news := ac.news.Load()
+	requests := ac.requests.Load()
+	return AcquireStats{Requests: requests, Reused: requests - news, New: news}
 }
 func (p PoolStats) AsDBStats() sql.DBStats {
 	return sql.DBStats{
@@ -889,6 +991,7 @@ func (d *drv) getPoolStats(p *connPool) (stats PoolStats, err error) {
 	}
 
 	stats.Max = uint32(p.params.PoolParams.MaxSessions)
+	stats.Acquires = p.acquires.stats()
 
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
@@ -915,6 +1018,7 @@ func (d *drv) getPoolStats(p *connPool) (stats PoolStats, err error) {
 type commonAndConnParams struct {
 	dsn.CommonParams
 	dsn.ConnParams
+	tag *sessionTag
 }
 
 func (P commonAndConnParams) String() string {
@@ -1171,6 +1275,13 @@ func (d *drv) OpenConnector(name string) (driver.Connector, error) {
 // The returned connection is only used by one goroutine at a
 // time.
 func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	ctx, ts := startSpan(ctx, "Connect", "")
+	conn, err := c.connect(ctx)
+	ts.end(err)
+	return conn, err
+}
+
+func (c connector) connect(ctx context.Context) (driver.Conn, error) {
 	params := c.ConnectionParams
 	logger := c.CommonParams.Logger
 	if ctxValue := ctx.Value(paramsCtxKey{}); ctxValue != nil {
@@ -1183,6 +1294,7 @@ func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
 			if logger != nil {
 				logger.Debug("connect with params from context", "poolParams", params.PoolParams, "connParams", cc, "common", cc.CommonParams)
 			}
+			applyDRCPFromContext(ctx, &cc.ConnParams)
 			return c.drv.createConnFromParams(ctx, dsn.ConnectionParams{
 				CommonParams: cc.CommonParams, ConnParams: cc.ConnParams,
 				PoolParams: params.PoolParams,
@@ -1197,6 +1309,7 @@ func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
 			params.ConnParams.ConnClass = up.ConnClass
 		}
 	}
+	applyDRCPFromContext(ctx, &params.ConnParams)
 
 	if logger != nil {
 		logger.Debug("connect", "poolParams", params.PoolParams, "connParams", params.ConnParams, "common", params.CommonParams)
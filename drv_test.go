@@ -100,3 +100,14 @@ func TestMarshalJSON(t *testing.T) {
 	}
 	t.Log(string(b))
 }
+
+func TestAcquireCounters(t *testing.T) {
+	t.Parallel()
+	var ac acquireCounters
+	for _, isNew := range []bool{true, false, false, true, false} {
+		ac.count(isNew)
+	}
+	if got, want := ac.stats(), (AcquireStats{Requests: 5, Reused: 3, New: 2}); got != want {
+		t.Errorf("got %+v, wanted %+v", got, want)
+	}
+}
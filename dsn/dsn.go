@@ -57,10 +57,17 @@ type CommonSimpleParams struct {
 	Charset                 string
 	// StmtCacheSize of 0 means the default, -1 to disable the stmt cache completely
 	StmtCacheSize int
+	// SlowQueryThreshold, if positive, is the elapsed time above which the executions are logged
+	// (with their SQL_ID) at Warn level.
+	SlowQueryThreshold time.Duration
 	// true: OnInit will be called only by the new session / false: OnInit will called by new or pooled connection
 	InitOnNewConn                               bool
 	EnableEvents, NoTZCheck, PerSessionTimezone bool
 	NoBreakOnContextCancel                      bool
+	// SlowQueryPlan adds the child number and plan hash value of the slow queries to the log,
+	// queried from V$SQL on the same session: this is an additional round-trip,
+	// and replaces the session's PREV_SQL_ID.
+	SlowQueryPlan bool
 }
 
 // CommonParams holds the common parameters for pooled or standalone connections.
@@ -77,6 +84,42 @@ type CommonParams struct {
 	OnInitStmts []string
 	// AlterSession key-values are set with "ALTER SESSION SET key=value" on session init, iff OnInit is nil.
 	AlterSession [][2]string
+	// OnSessionTag is called when the pooled session does not have the tag requested
+	// (with godror.ContextWithSessionTag), to set the session's state as the tag requires.
+	// actual is the tag of the session (empty for untagged ones).
+	// On success, the session is retagged with the requested tag when it is released to the pool,
+	// so the next request for this tag will skip OnInit and OnSessionTag.
+	// If OnSessionTag is nil, the session is retagged after OnInit has run, so the tag stands for OnInit's state.
+	OnSessionTag func(ctx context.Context, conn driver.ConnPrepareContext, requested, actual string) error
+	// Interceptors wrap the Prepare, Exec, Query, Commit and Rollback calls of the connections,
+	// the first being the outermost.
+	Interceptors []Interceptor
+}
+
+// Interceptor wraps a database operation: it must call next to proceed,
+// and can inspect (or replace) the results in call after next returned.
+//
+// It may modify call.SQL before calling next in "Prepare", call.Args in "Exec" and "Query",
+// or return an error without calling next to reject the operation.
+// When it returns an error after next, the driver closes call.Stmt or call.Rows.
+type Interceptor func(ctx context.Context, call *InterceptedCall, next func(context.Context) error) error
+
+// InterceptedCall is the operation intercepted by an Interceptor.
+type InterceptedCall struct {
+	// Stmt is the prepared statement, set after "Prepare".
+	Stmt driver.Stmt
+	// Result is the result of "Exec".
+	Result driver.Result
+	// Rows are the rows of "Query".
+	Rows driver.Rows
+	// Operation is "Prepare", "Exec", "Query", "Commit" or "Rollback".
+	Operation string
+	// SQL is the statement text (empty for Commit and Rollback).
+	SQL string
+	// Args are the arguments of Exec and Query.
+	Args []driver.NamedValue
+	// Duration of the operation (without the interceptors), set after next returned.
+	Duration time.Duration
 }
 
 func (P CommonParams) String() string {
@@ -136,6 +179,12 @@ func (P CommonSimpleParams) String() string {
 	if P.NoBreakOnContextCancel {
 		q.Add("noBreakOnContextCancel", "1")
 	}
+	if P.SlowQueryThreshold > 0 {
+		q.Add("slowQueryThreshold", P.SlowQueryThreshold.String())
+	}
+	if P.SlowQueryPlan {
+		q.Add("slowQueryPlan", "1")
+	}
 
 	s = q.String()
 	cacheCPSMu.Lock()
@@ -152,6 +201,47 @@ type ConnParams struct {
 	ConnClass                               string
 	ShardingKey, SuperShardingKey           []interface{}
 	IsSysDBA, IsSysOper, IsSysASM, IsPrelim bool
+	// Purity of the DRCP session: whether a session used by the same connection class can be reused.
+	Purity Purity
+}
+
+// Purity is the purity of a DRCP (Database Resident Connection Pooling) session.
+type Purity uint8
+
+// The session purities, with the same values as ODPI-C's DPI_PURITY_*.
+const (
+	// PurityDefault is PuritySelf for pooled, PurityNew for standalone connections.
+	PurityDefault = Purity(0)
+	// PurityNew requests a new session, without the state left by a previous user.
+	PurityNew = Purity(1)
+	// PuritySelf allows reusing a session of the same connection class.
+	PuritySelf = Purity(2)
+)
+
+// String returns the purity as in the "purity" parameter: "", "new" or "self".
+func (p Purity) String() string {
+	switch p {
+	case PurityNew:
+		return "new"
+	case PuritySelf:
+		return "self"
+	default:
+		return ""
+	}
+}
+
+// ParsePurity parses the purity ("new", "self", or "default" or empty).
+func ParsePurity(s string) (Purity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "default":
+		return PurityDefault, nil
+	case "new":
+		return PurityNew, nil
+	case "self":
+		return PuritySelf, nil
+	default:
+		return PurityDefault, fmt.Errorf("unknown purity %q (should be new, self or default)", s)
+	}
 }
 
 // String returns the string representation of the ConnParams.
@@ -161,6 +251,9 @@ func (P ConnParams) String() string {
 	if P.ConnClass != "" {
 		q.Add("connectionClass", P.ConnClass)
 	}
+	if P.Purity != PurityDefault {
+		q.Add("purity", P.Purity.String())
+	}
 	if !P.NewPassword.IsZero() {
 		q.Add("newPassword", P.NewPassword.String())
 	}
@@ -285,6 +378,9 @@ func (P ConnectionParams) string(class, withPassword bool) string {
 		s = ""
 	}
 	q.Add("connectionClass", s)
+	if P.Purity != PurityDefault {
+		q.Add("purity", P.Purity.String())
+	}
 
 	q.Add("user", P.Username)
 	if withPassword {
@@ -346,6 +442,12 @@ func (P ConnectionParams) string(class, withPassword bool) string {
 	}
 	q.Add("initOnNewConnection", B(P.InitOnNewConn))
 	q.Add("noBreakOnContextCancel", B(P.NoBreakOnContextCancel))
+	if P.SlowQueryThreshold > 0 {
+		q.Add("slowQueryThreshold", P.SlowQueryThreshold.String())
+	}
+	if P.SlowQueryPlan {
+		q.Add("slowQueryPlan", "1")
+	}
 	q.Values["onInit"] = P.OnInitStmts
 	q.Add("configDir", P.ConfigDir)
 	q.Add("libDir", P.LibDir)
@@ -468,6 +570,12 @@ func Parse(dataSourceName string) (ConnectionParams, error) {
 	if vv, ok := q["connectionClass"]; ok {
 		P.ConnClass = vv[0]
 	}
+	if vv, ok := q["purity"]; ok {
+		var err error
+		if P.Purity, err = ParsePurity(vv[0]); err != nil {
+			return P, err
+		}
+	}
 	for _, task := range []struct {
 		Dest *bool
 		Key  string
@@ -486,6 +594,7 @@ func Parse(dataSourceName string) (ConnectionParams, error) {
 		{&P.PerSessionTimezone, "perSessionTimezone"},
 		{&P.InitOnNewConn, "initOnNewConnection"},
 		{&P.NoBreakOnContextCancel, "noBreakOnContextCancel"},
+		{&P.SlowQueryPlan, "slowQueryPlan"},
 	} {
 		s := q.Get(task.Key)
 		if s == "" {
@@ -554,6 +663,7 @@ func Parse(dataSourceName string) (ConnectionParams, error) {
 		{&P.WaitTimeout, "poolWaitTimeout"},
 		{&P.MaxLifeTime, "poolSessionMaxLifetime"},
 		{&P.PingInterval, "pingInterval"},
+		{&P.SlowQueryThreshold, "slowQueryThreshold"},
 	} {
 		s := q.Get(task.Key)
 		if s == "" {
@@ -0,0 +1,152 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include "dpiImpl.h"
+*/
+import "C"
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAdaptiveFetchBudget is the memory budget of AdaptiveFetch, if not given.
+	DefaultAdaptiveFetchBudget = 4 << 20
+
+	adaptiveFetchStart      = 16
+	adaptiveFetchMax        = 1 << 16
+	adaptiveFetchMaxLatency = 250 * time.Millisecond
+	adaptiveFetchCacheSize  = 1024
+)
+
+// AdaptiveFetch is an option to adapt the fetch array size (see FetchArraySize) to the query:
+// the first execution of a SQL text starts with a small fetch array size, and doubles it
+// after each full, fast fetch, as long as the fetch buffers (computed from the columns' sizes)
+// fit in memoryBudget bytes (DefaultAdaptiveFetchBudget if <= 0).
+//
+// A fetch is considered fast if it takes less than 250ms - slower fetches are dominated
+// by the server's work, not the round-trips, so there's no use of growing the array further.
+//
+// The learned array size is remembered per SQL text, and the succeeding executions start with it.
+//
+// Queries with LOB, LONG, object or cursor columns are not adapted.
+//
+// Use it "naked", without sql.Named!
+func AdaptiveFetch(memoryBudget int) Option {
+	if memoryBudget <= 0 {
+		memoryBudget = DefaultAdaptiveFetchBudget
+	}
+	return func(o *stmtOptions) { o.adaptiveFetchBudget = memoryBudget }
+}
+
+// fetchSizeCache holds the learned fetch array sizes, per SQL text.
+type fetchSizeCache struct {
+	m  map[string]int
+	mu sync.Mutex
+}
+
+var adaptiveFetchSizes = fetchSizeCache{m: make(map[string]int)}
+
+// get the learned fetch array size of the query, or the starting size.
+func (c *fetchSizeCache) get(qry string) int {
+	c.mu.Lock()
+	n := c.m[qry]
+	c.mu.Unlock()
+	if n == 0 {
+		return adaptiveFetchStart
+	}
+	return n
+}
+
+func (c *fetchSizeCache) set(qry string, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.m[qry]; !ok && len(c.m) >= adaptiveFetchCacheSize {
+		for k := range c.m { // forget a random one
+			delete(c.m, k)
+			break
+		}
+	}
+	c.m[qry] = n
+}
+
+// rowWidth returns the memory needed by the fetch buffers for one row,
+// or 0 if the columns' buffers cannot be resized.
+func rowWidth(columns []Column) int {
+	var width int
+	for _, col := range columns {
+		switch col.OracleType {
+		case C.DPI_ORACLE_TYPE_VARCHAR, C.DPI_ORACLE_TYPE_NVARCHAR,
+			C.DPI_ORACLE_TYPE_CHAR, C.DPI_ORACLE_TYPE_NCHAR,
+			C.DPI_ORACLE_TYPE_RAW, C.DPI_ORACLE_TYPE_ROWID,
+			C.DPI_ORACLE_TYPE_NUMBER,
+			C.DPI_ORACLE_TYPE_NATIVE_INT, C.DPI_ORACLE_TYPE_NATIVE_UINT,
+			C.DPI_ORACLE_TYPE_NATIVE_FLOAT, C.DPI_ORACLE_TYPE_NATIVE_DOUBLE,
+			C.DPI_ORACLE_TYPE_DATE, C.DPI_ORACLE_TYPE_TIMESTAMP,
+			C.DPI_ORACLE_TYPE_TIMESTAMP_TZ, C.DPI_ORACLE_TYPE_TIMESTAMP_LTZ,
+			C.DPI_ORACLE_TYPE_INTERVAL_DS, C.DPI_ORACLE_TYPE_INTERVAL_YM,
+			C.DPI_ORACLE_TYPE_BOOLEAN:
+			width += maxI(int(col.Size), 8) + C.sizeof_dpiData
+		default:
+			return 0
+		}
+	}
+	return width
+}
+
+// adaptFetchArraySize decides the fetch array size of the next fetch, after a fetch of fetched rows
+// (of the maximum maxRows) in dur time.
+func (r *rows) adaptFetchArraySize(maxRows, fetched int, dur time.Duration) {
+	if r.rowWidth == 0 || fetched < maxRows || dur >= adaptiveFetchMaxLatency {
+		return
+	}
+	n := 2 * maxRows
+	if limit := r.adaptiveFetchBudget / r.rowWidth; n > limit {
+		n = limit
+	}
+	if n > adaptiveFetchMax {
+		n = adaptiveFetchMax
+	}
+	if n > maxRows {
+		r.growFetchTo = n
+	}
+}
+
+// growFetchArraySize defines new, bigger variables for the columns, for fetching r.growFetchTo rows at once.
+//
+// Must be called only after all the fetched rows have been consumed.
+func (r *rows) growFetchArraySize() error {
+	n := r.growFetchTo
+	r.growFetchTo = 0
+	st := r.statement
+	for i, vi := range r.defines {
+		vi.SliceLen = n
+		v, _, err := st.newVar(vi)
+		if err != nil {
+			return err
+		}
+		if err = st.checkExecNoLOT(func() C.int {
+			return C.dpiStmt_define(st.dpiStmt, C.uint32_t(i+1), v)
+		}); err != nil {
+			C.dpiVar_release(v)
+			return fmt.Errorf("define[%d]: %w", i, err)
+		}
+		C.dpiVar_release(r.vars[i])
+		r.vars[i], r.defines[i] = v, vi
+	}
+	if err := st.checkExecNoLOT(func() C.int {
+		return C.dpiStmt_setFetchArraySize(st.dpiStmt, C.uint32_t(n))
+	}); err != nil {
+		return fmt.Errorf("setFetchArraySize(%d): %w", n, err)
+	}
+	r.data = nil
+	st.fetchArraySize = n
+	adaptiveFetchSizes.set(st.query, n)
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAdaptFetchArraySize(t *testing.T) {
+	r := rows{statement: &statement{stmtOptions: stmtOptions{adaptiveFetchBudget: 1 << 20}}, rowWidth: 100}
+	for _, tC := range []struct {
+		MaxRows, Fetched int
+		Dur              time.Duration
+		Want             int
+	}{
+		{MaxRows: 16, Fetched: 16, Want: 32},
+		{MaxRows: 16, Fetched: 10, Want: 0},
+		{MaxRows: 16, Fetched: 16, Dur: time.Second, Want: 0},
+		{MaxRows: 8192, Fetched: 8192, Want: (1 << 20) / 100},
+		{MaxRows: (1 << 20) / 100, Fetched: (1 << 20) / 100, Want: 0},
+	} {
+		r.growFetchTo = 0
+		r.adaptFetchArraySize(tC.MaxRows, tC.Fetched, tC.Dur)
+		if r.growFetchTo != tC.Want {
+			t.Errorf("%+v: got %d", tC, r.growFetchTo)
+		}
+	}
+}
+
+func TestFetchSizeCache(t *testing.T) {
+	c := fetchSizeCache{m: make(map[string]int)}
+	if got := c.get("x"); got != adaptiveFetchStart {
+		t.Errorf("got %d, wanted %d", got, adaptiveFetchStart)
+	}
+	c.set("x", 128)
+	if got := c.get("x"); got != 128 {
+		t.Errorf("got %d, wanted 128", got)
+	}
+	for i := 0; i < 2*adaptiveFetchCacheSize; i++ {
+		c.set(strconv.Itoa(i), i+1)
+	}
+	if len(c.m) > adaptiveFetchCacheSize {
+		t.Errorf("cache grew to %d", len(c.m))
+	}
+}
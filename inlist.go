@@ -0,0 +1,275 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/godror/godror/sqllex"
+)
+
+// MaxInListLen is the maximum number of expressions in an IN list (ORA-01795).
+const MaxInListLen = 1000
+
+// ExpandInLists is an option to expand the slice arguments into lists of bind variables,
+// for using them in IN lists, instead of executing the statement for each element (ExecMany).
+//
+// For example
+//
+//	db.QueryContext(ctx, "SELECT * FROM T WHERE id IN (:ids)", godror.ExpandInLists(), sql.Named("ids", []int{1, 2, 3}))
+//
+// executes "SELECT * FROM T WHERE id IN (:ids_1, :ids_2, :ids_3, :ids_4)", binding 1, 2, 3, 3.
+//
+// The number of bind variables is rounded up to the next power of two (and at most MaxInListLen)
+// by repeating the last element, to keep the number of different statements
+// (and thus the statement cache misses) low.
+// An empty slice is bound as one NULL.
+//
+// The bind variables in strings and comments are left intact,
+// and positional arguments must match the bind variables one by one.
+//
+// Use it "naked", without sql.Named!
+func ExpandInLists() Option { return func(o *stmtOptions) { o.inListExpand = true } }
+
+// InListAsCollection is an option to bind the slice arguments as a collection of the given type
+// (as returned by GetObjectType), instead of executing the statement for each element (ExecMany).
+//
+// The type must be a SQL (nested table or varray) collection type, such as SYS.ODCINUMBERLIST or SYS.ODCIVARCHAR2LIST,
+// and the query must use it with TABLE:
+//
+//	db.QueryContext(ctx, "SELECT * FROM T WHERE id IN (SELECT COLUMN_VALUE FROM TABLE(:ids))",
+//	  godror.InListAsCollection("SYS.ODCINUMBERLIST"), sql.Named("ids", []int{1, 2, 3}))
+//
+// This has no limit on the number of elements, and the statement text does not depend on it.
+//
+// Use it "naked", without sql.Named!
+func InListAsCollection(typeName string) Option {
+	return func(o *stmtOptions) { o.inListType = typeName }
+}
+
+// inListBucket returns the number of bind variables for an IN list of n elements.
+func inListBucket(n int) int {
+	b := 1
+	for b < n {
+		b <<= 1
+	}
+	if b > MaxInListLen {
+		return MaxInListLen
+	}
+	return b
+}
+
+// inListSlice returns the argument as a slice, iff it can be an IN list.
+func inListSlice(v interface{}) (reflect.Value, bool) {
+	if v == nil {
+		return reflect.Value{}, false
+	}
+	switch v.(type) {
+	case []byte, driver.Valuer:
+		return reflect.Value{}, false
+	}
+	rv := reflect.ValueOf(v)
+	return rv, rv.Kind() == reflect.Slice
+}
+
+// expandInLists rewrites the query, replacing the bind variables of the slice arguments
+// with as many bind variables as the slice has elements (see ExpandInLists).
+//
+// Returns the empty string if there is nothing to expand.
+func expandInLists(qry string, args []driver.NamedValue) (string, []driver.NamedValue, error) {
+	var named, hasSlice bool
+	for _, a := range args {
+		if _, ok := inListSlice(a.Value); ok {
+			hasSlice = true
+		}
+		named = named || a.Name != ""
+	}
+	if !hasSlice {
+		return "", nil, nil
+	}
+
+	elements := func(rv reflect.Value) ([]interface{}, error) {
+		n := rv.Len()
+		if n > MaxInListLen {
+			return nil, fmt.Errorf("%d elements is more than the allowed %d in an IN list (use InListAsCollection)", n, MaxInListLen)
+		}
+		if n == 0 {
+			return []interface{}{nil}, nil
+		}
+		elts := make([]interface{}, inListBucket(n))
+		for i := range elts {
+			elts[i] = rv.Index(minI(i, n-1)).Interface()
+		}
+		return elts, nil
+	}
+
+	var buf strings.Builder
+	buf.Grow(len(qry) + 64)
+	newArgs := make([]driver.NamedValue, 0, len(args)+16)
+	var last, pos int
+	lx := sqllex.New(qry)
+	if !named {
+		// positional binding: the i-th bind variable gets the i-th argument
+		for t := lx.Next(); t.Kind != sqllex.EOF; t = lx.Next() {
+			if t.Kind != sqllex.BindVar {
+				continue
+			}
+			if pos >= len(args) {
+				return "", nil, fmt.Errorf("%s: more bind variables than the %d arguments", qry, len(args))
+			}
+			buf.WriteString(qry[last:t.Offset])
+			last = t.Offset + len(t.Text)
+			a := args[pos]
+			pos++
+			elts := []interface{}{a.Value}
+			if rv, ok := inListSlice(a.Value); ok {
+				var err error
+				if elts, err = elements(rv); err != nil {
+					return "", nil, fmt.Errorf("%d. arg: %w", pos, err)
+				}
+			}
+			for i, v := range elts {
+				if i != 0 {
+					buf.WriteString(", ")
+				}
+				newArgs = append(newArgs, driver.NamedValue{Ordinal: len(newArgs) + 1, Value: v})
+				buf.WriteByte(':')
+				buf.WriteString(strconv.Itoa(len(newArgs)))
+			}
+		}
+		if pos != len(args) {
+			return "", nil, fmt.Errorf("%s: %d bind variables for %d arguments", qry, pos, len(args))
+		}
+		buf.WriteString(qry[last:])
+		return buf.String(), newArgs, nil
+	}
+
+	// named binding: each occurrence of :name is replaced with :name_1, :name_2...
+	slices := make(map[string][]interface{})
+	for _, a := range args {
+		rv, ok := inListSlice(a.Value)
+		if !ok {
+			newArgs = append(newArgs, a)
+			continue
+		}
+		if a.Name == "" {
+			return "", nil, fmt.Errorf("%d. arg: cannot mix positional and named arguments", a.Ordinal)
+		}
+		elts, err := elements(rv)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: %w", a.Name, err)
+		}
+		slices[strings.ToUpper(a.Name)] = elts
+		for i, v := range elts {
+			newArgs = append(newArgs, driver.NamedValue{Name: a.Name + "_" + strconv.Itoa(i+1), Value: v})
+		}
+	}
+	for t := lx.Next(); t.Kind != sqllex.EOF; t = lx.Next() {
+		if t.Kind != sqllex.BindVar {
+			continue
+		}
+		name := t.BindName()
+		elts, ok := slices[strings.ToUpper(name)]
+		if !ok {
+			continue
+		}
+		buf.WriteString(qry[last:t.Offset])
+		last = t.Offset + len(t.Text)
+		for i := range elts {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteByte(':')
+			buf.WriteString(name)
+			buf.WriteByte('_')
+			buf.WriteString(strconv.Itoa(i + 1))
+		}
+	}
+	if last == 0 {
+		return "", nil, errors.New("no bind variable found for the slice arguments")
+	}
+	buf.WriteString(qry[last:])
+	return buf.String(), newArgs, nil
+}
+
+// prepareInLists prepares the query expanded by ExpandInLists.
+//
+// Returns nil if there is nothing to expand.
+func (st *statement) prepareInLists(ctx context.Context, args []driver.NamedValue) (*statement, []driver.NamedValue, error) {
+	qry, newArgs, err := expandInLists(st.query, args)
+	if err != nil || qry == "" {
+		return nil, nil, err
+	}
+	logger := getLogger(ctx)
+	if logger != nil {
+		logger.Debug("ExpandInLists", "qry", qry)
+	}
+	dst, err := st.conn.PrepareContext(ctx, qry)
+	if err != nil {
+		return nil, nil, err
+	}
+	st2 := dst.(*statement)
+	st2.stmtOptions = st.stmtOptions
+	st2.inListExpand = false
+	return st2, newArgs, nil
+}
+
+// inListCollections replaces the slice arguments with collections of the InListAsCollection type.
+//
+// The returned function must be called to close the collections, after the execution.
+func (st *statement) inListCollections(args []driver.NamedValue) ([]driver.NamedValue, func(), error) {
+	var objs []*Object
+	closeObjs := func() {
+		for _, O := range objs {
+			O.Close()
+		}
+	}
+	var newArgs []driver.NamedValue
+	for i, a := range args {
+		rv, ok := inListSlice(a.Value)
+		if !ok {
+			continue
+		}
+		if newArgs == nil {
+			newArgs = append(make([]driver.NamedValue, 0, len(args)), args...)
+		}
+		ot, err := st.conn.GetObjectType(st.inListType)
+		if err != nil {
+			closeObjs()
+			return args, nil, err
+		}
+		coll, err := ot.NewCollection()
+		if err != nil {
+			closeObjs()
+			return args, nil, fmt.Errorf("%s: %w", st.inListType, err)
+		}
+		objs = append(objs, coll.Object)
+		for j, n := 0, rv.Len(); j < n; j++ {
+			if err = coll.Append(rv.Index(j).Interface()); err != nil {
+				closeObjs()
+				return args, nil, fmt.Errorf("%d. arg: append %d. element: %w", i+1, j, err)
+			}
+		}
+		newArgs[i].Value = coll.Object
+	}
+	if newArgs == nil {
+		return args, func() {}, nil
+	}
+	return newArgs, closeObjs, nil
+}
+
+func minI(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
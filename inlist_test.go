@@ -0,0 +1,74 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestExpandInLists(t *testing.T) {
+	for tN, tC := range []struct {
+		Qry, Want string
+		Args      []driver.NamedValue
+		WantArgs  []driver.NamedValue
+	}{
+		{
+			Qry:  "SELECT 1 FROM DUAL WHERE :x = 1",
+			Args: []driver.NamedValue{{Ordinal: 1, Value: 1}},
+		},
+		{
+			Qry:  "SELECT * FROM T WHERE id IN (:ids) AND txt <> ':ids' AND x = :x",
+			Want: "SELECT * FROM T WHERE id IN (:1, :2, :3, :4) AND txt <> ':ids' AND x = :5",
+			Args: []driver.NamedValue{{Ordinal: 1, Value: []int{1, 2, 3}}, {Ordinal: 2, Value: "x"}},
+			WantArgs: []driver.NamedValue{
+				{Ordinal: 1, Value: 1}, {Ordinal: 2, Value: 2}, {Ordinal: 3, Value: 3}, {Ordinal: 4, Value: 3},
+				{Ordinal: 5, Value: "x"},
+			},
+		},
+		{
+			Qry:  "SELECT * FROM T WHERE id IN (:ids) /* :ids */ OR id2 IN (:IDS) AND x = :x",
+			Want: "SELECT * FROM T WHERE id IN (:ids_1, :ids_2) /* :ids */ OR id2 IN (:IDS_1, :IDS_2) AND x = :x",
+			Args: []driver.NamedValue{{Name: "x", Value: "x"}, {Name: "ids", Value: []string{"a", "b"}}},
+			WantArgs: []driver.NamedValue{
+				{Name: "x", Value: "x"}, {Name: "ids_1", Value: "a"}, {Name: "ids_2", Value: "b"},
+			},
+		},
+		{
+			Qry:      "SELECT * FROM T WHERE id IN (:1)",
+			Want:     "SELECT * FROM T WHERE id IN (:1)",
+			Args:     []driver.NamedValue{{Ordinal: 1, Value: []int{}}},
+			WantArgs: []driver.NamedValue{{Ordinal: 1, Value: nil}},
+		},
+	} {
+		got, gotArgs, err := expandInLists(tC.Qry, tC.Args)
+		if err != nil {
+			t.Errorf("%d. %+v", tN, err)
+			continue
+		}
+		if got != tC.Want {
+			t.Errorf("%d. got\n%q wanted\n%q", tN, got, tC.Want)
+		}
+		if !reflect.DeepEqual(gotArgs, tC.WantArgs) {
+			t.Errorf("%d. got args\n%#v wanted\n%#v", tN, gotArgs, tC.WantArgs)
+		}
+	}
+
+	if _, _, err := expandInLists("SELECT :1 FROM DUAL", []driver.NamedValue{
+		{Ordinal: 1, Value: make([]int, MaxInListLen+1)},
+	}); err == nil {
+		t.Error("wanted error for too long IN list")
+	}
+}
+
+func TestInListBucket(t *testing.T) {
+	for _, tC := range [][2]int{{0, 1}, {1, 1}, {2, 2}, {3, 4}, {9, 16}, {512, 512}, {513, MaxInListLen}, {MaxInListLen, MaxInListLen}} {
+		if got := inListBucket(tC[0]); got != tC[1] {
+			t.Errorf("%d: got %d, wanted %d", tC[0], got, tC[1])
+		}
+	}
+}
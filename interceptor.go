@@ -0,0 +1,50 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var errNoInterceptedResult = errors.New("interceptor returned neither result nor error")
+
+// intercept calls f through the Interceptors of the connection (the first being the outermost),
+// setting call.Duration to the duration of f.
+//
+// If the interceptors return an error, the Stmt or Rows of call are closed,
+// as database/sql drops them on error.
+func (c *conn) intercept(ctx context.Context, call *InterceptedCall, f func(context.Context) error) error {
+	if c == nil || len(c.params.Interceptors) == 0 {
+		return f(ctx)
+	}
+	interceptors := c.params.Interceptors
+	var next func(int) func(context.Context) error
+	next = func(i int) func(context.Context) error {
+		if i == len(interceptors) {
+			return func(ctx context.Context) error {
+				start := time.Now()
+				err := f(ctx)
+				call.Duration = time.Since(start)
+				return err
+			}
+		}
+		return func(ctx context.Context) error { return interceptors[i](ctx, call, next(i+1)) }
+	}
+	err := next(0)(ctx)
+	if err != nil {
+		if call.Stmt != nil {
+			_ = call.Stmt.Close()
+			call.Stmt = nil
+		}
+		if call.Rows != nil {
+			_ = call.Rows.Close()
+			call.Rows = nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntercept(t *testing.T) {
+	t.Parallel()
+	var order []string
+	mk := func(name string) Interceptor {
+		return func(ctx context.Context, call *InterceptedCall, next func(context.Context) error) error {
+			order = append(order, name+">")
+			call.SQL += " /* " + name + " */"
+			err := next(ctx)
+			order = append(order, "<"+name)
+			return err
+		}
+	}
+	var c conn
+	c.params.Interceptors = []Interceptor{mk("a"), mk("b")}
+	call := InterceptedCall{Operation: "Prepare", SQL: "SELECT 1 FROM DUAL"}
+	var got string
+	if err := c.intercept(context.Background(), &call, func(context.Context) error {
+		order = append(order, "f")
+		got = call.SQL
+		time.Sleep(time.Millisecond)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT 1 FROM DUAL /* a */ /* b */"; got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+	if s, want := strings.Join(order, " "), "a> b> f <b <a"; s != want {
+		t.Errorf("got order %q, wanted %q", s, want)
+	}
+	if call.Duration < time.Millisecond {
+		t.Errorf("got duration %s", call.Duration)
+	}
+
+	errRejected := errors.New("rejected")
+	c.params.Interceptors = []Interceptor{func(context.Context, *InterceptedCall, func(context.Context) error) error {
+		return errRejected
+	}}
+	var called bool
+	if err := c.intercept(context.Background(), &call, func(context.Context) error {
+		called = true
+		return nil
+	}); !errors.Is(err, errRejected) || called {
+		t.Errorf("got %v (called=%t), wanted rejection", err, called)
+	}
+}
+
+func TestInterceptRejectAfterNext(t *testing.T) {
+	t.Parallel()
+	errRejected := errors.New("rejected")
+	var c conn
+	c.params.Interceptors = []Interceptor{func(ctx context.Context, call *InterceptedCall, next func(context.Context) error) error {
+		if err := next(ctx); err != nil {
+			return err
+		}
+		return errRejected
+	}}
+
+	var stmt closeCounter
+	call := InterceptedCall{Operation: "Prepare", SQL: "SELECT 1 FROM DUAL"}
+	if err := c.intercept(context.Background(), &call, func(context.Context) error {
+		call.Stmt = &stmt
+		return nil
+	}); !errors.Is(err, errRejected) {
+		t.Errorf("got %v, wanted rejection", err)
+	}
+	if stmt.closed != 1 || call.Stmt != nil {
+		t.Errorf("stmt closed %d times, call.Stmt=%v", stmt.closed, call.Stmt)
+	}
+
+	var rows closeCounter
+	call = InterceptedCall{Operation: "Query", SQL: "SELECT 1 FROM DUAL"}
+	if err := c.intercept(context.Background(), &call, func(context.Context) error {
+		call.Rows = &rows
+		return nil
+	}); !errors.Is(err, errRejected) {
+		t.Errorf("got %v, wanted rejection", err)
+	}
+	if rows.closed != 1 || call.Rows != nil {
+		t.Errorf("rows closed %d times, call.Rows=%v", rows.closed, call.Rows)
+	}
+}
+
+// closeCounter is a driver.Stmt and driver.Rows which counts its Close calls.
+type closeCounter struct {
+	driver.Stmt
+	driver.Rows
+	closed int
+}
+
+func (cc *closeCounter) Close() error { cc.closed++; return nil }
@@ -12,6 +12,8 @@ import "C"
 import (
 	"bufio"
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"io"
@@ -46,7 +48,7 @@ func (lob *Lob) Hijack() (*DirectLob, error) {
 		return nil, fmt.Errorf("Lob.Reader is %T, not *dpiLobReader", lob.Reader)
 	}
 	lob.Reader = nil
-	return &DirectLob{drv: lr.drv, dpiLob: lr.dpiLob}, nil
+	return &DirectLob{drv: lr.drv, conn: lr.conn, dpiLob: lr.dpiLob, isClob: lr.IsClob}, nil
 }
 
 // WriteTo writes data to w until there's no more data to write or when an error occurs.
@@ -112,6 +114,9 @@ var _ = io.ReaderAt((*dpiLobReader)(nil))
 
 type dpiLobReader struct {
 	*drv
+	// ctx is the context of the query, only for tracing the reads.
+	ctx                 context.Context
+	conn                *conn
 	dpiLob              *C.dpiLob
 	buf                 []byte
 	offset, sizePlusOne C.uint64_t
@@ -300,9 +305,12 @@ func (dlr *dpiLobReader) read(p []byte) (int, error) {
 		}
 		return 0, io.EOF
 	}
-	if err := dlr.drv.checkExecNoLOT(func() C.int {
+	_, ts := startSpan(dlr.ctx, "LobRead", "")
+	err := dlr.drv.checkExecNoLOT(func() C.int {
 		return C.dpiLob_readBytes(dlr.dpiLob, dlr.offset+1, amount, (*C.char)(unsafe.Pointer(&p[0])), &n)
-	}); err != nil {
+	})
+	ts.end(err)
+	if err != nil {
 		if logger != nil {
 			logger.Error("readBytes", "error", err)
 		}
@@ -323,7 +331,6 @@ func (dlr *dpiLobReader) read(p []byte) (int, error) {
 	} else {
 		dlr.offset += n
 	}
-	var err error
 	if amount != 0 && n == 0 || !dlr.IsClob && dlr.offset+1 >= dlr.sizePlusOne {
 		C.dpiLob_close(dlr.dpiLob)
 		dlr.dpiLob = nil
@@ -435,8 +442,11 @@ func closeLob(d interface{ getError() error }, lob *C.dpiLob) error {
 // DirectLob holds a Lob and allows direct (Read/WriteAt, not streaming Read/Write) operations on it.
 type DirectLob struct {
 	drv            *drv
+	conn           *conn
 	dpiLob         *C.dpiLob
 	opened, isClob bool
+	// owned is true for the LOBs holding their own reference (see Copy), which Close releases even if not opened.
+	owned bool
 }
 
 var _ = io.ReaderAt((*DirectLob)(nil))
@@ -448,7 +458,7 @@ func (c *conn) NewTempLob(isClob bool) (*DirectLob, error) {
 	if isClob {
 		typ = C.DPI_ORACLE_TYPE_CLOB
 	}
-	lob := DirectLob{drv: c.drv, isClob: isClob}
+	lob := DirectLob{drv: c.drv, conn: c, isClob: isClob}
 	if err := c.checkExec(func() C.int { return C.dpiConn_newTempLob(c.dpiConn, typ, &lob.dpiLob) }); err != nil {
 		return nil, fmt.Errorf("newTempLob: %w", err)
 	}
@@ -457,11 +467,11 @@ func (c *conn) NewTempLob(isClob bool) (*DirectLob, error) {
 
 // Close the Lob.
 func (dl *DirectLob) Close() error {
-	if !dl.opened {
+	if !(dl.opened || dl.owned) {
 		return nil
 	}
 	lob := dl.dpiLob
-	dl.opened, dl.dpiLob = false, nil
+	dl.opened, dl.owned, dl.dpiLob = false, false, nil
 	return closeLob(dl.drv, lob)
 }
 
@@ -582,6 +592,177 @@ func (dl *DirectLob) GetFileName() (dir, file string, err error) {
 	return dir, file, nil
 }
 
+// Copy returns an independent copy of the LOB, with a new locator.
+// For persistent LOBs this is a copy of the locator (the data is copied on write),
+// for temporary LOBs a new temporary LOB with the same contents.
+//
+// The copy must be closed.
+func (dl *DirectLob) Copy() (*DirectLob, error) {
+	var lob *C.dpiLob
+	if err := dl.drv.checkExec(func() C.int { return C.dpiLob_copy(dl.dpiLob, &lob) }); err != nil {
+		return nil, fmt.Errorf("copy: %w", err)
+	}
+	return &DirectLob{drv: dl.drv, conn: dl.conn, dpiLob: lob, isClob: dl.isClob, owned: true}, nil
+}
+
+// Append the whole src LOB to the end of the LOB, on the server (DBMS_LOB.APPEND).
+func (dl *DirectLob) Append(ctx context.Context, src *DirectLob) error {
+	if err := dl.exec(ctx, "BEGIN DBMS_LOB.APPEND(:lob, :src); END;", sql.Named("src", src)); err != nil {
+		return fmt.Errorf("append: %w", err)
+	}
+	return nil
+}
+
+// CopyFrom copies amount bytes (characters for CLOBs) of src, starting at srcOffset,
+// into the LOB at dstOffset, on the server (DBMS_LOB.COPY).
+//
+// The offsets are 0-based, as in ReadAt and WriteAt.
+func (dl *DirectLob) CopyFrom(ctx context.Context, src *DirectLob, amount, dstOffset, srcOffset int64) error {
+	if err := dl.exec(ctx, "BEGIN DBMS_LOB.COPY(:lob, :src, :amount, :dstOffset, :srcOffset); END;",
+		sql.Named("src", src), sql.Named("amount", amount),
+		sql.Named("dstOffset", dstOffset+1), sql.Named("srcOffset", srcOffset+1),
+	); err != nil {
+		return fmt.Errorf("copy %d from %d to %d: %w", amount, srcOffset, dstOffset, err)
+	}
+	return nil
+}
+
+// Compare amount bytes (characters for CLOBs) of the LOB starting at offset
+// with the other LOB starting at otherOffset, on the server (DBMS_LOB.COMPARE).
+//
+// Returns 0 if they're the same, and non-zero otherwise.
+// amount <= 0 means compare till the end.
+func (dl *DirectLob) Compare(ctx context.Context, other *DirectLob, amount, offset, otherOffset int64) (int, error) {
+	var res sql.NullInt64
+	qry := "BEGIN :res := DBMS_LOB.COMPARE(:lob, :other, :amount, :offset, :otherOffset); END;"
+	if amount <= 0 {
+		qry = "BEGIN :res := DBMS_LOB.COMPARE(:lob, :other, DBMS_LOB.LOBMAXSIZE, :offset, :otherOffset); END;"
+	}
+	args := []sql.NamedArg{
+		sql.Named("res", sql.Out{Dest: &res}), sql.Named("other", other),
+		sql.Named("offset", offset+1), sql.Named("otherOffset", otherOffset+1),
+	}
+	if amount > 0 {
+		args = append(args, sql.Named("amount", amount))
+	}
+	if err := dl.exec(ctx, qry, args...); err != nil {
+		return 0, fmt.Errorf("compare: %w", err)
+	}
+	if !res.Valid {
+		return 0, fmt.Errorf("compare: invalid amount (%d) or offsets (%d, %d)", amount, offset, otherOffset)
+	}
+	return int(res.Int64), nil
+}
+
+// Index returns the 0-based offset of the nth (1-based) occurrence of the pattern in the LOB,
+// starting the search at offset, or -1 if the pattern is not found (DBMS_LOB.INSTR).
+//
+// For CLOBs, the pattern is handled as a string, and the offsets are in characters.
+func (dl *DirectLob) Index(ctx context.Context, pattern []byte, offset int64, nth int) (int64, error) {
+	var res sql.NullInt64
+	var pat interface{} = pattern
+	if dl.checkIsClob() {
+		pat = string(pattern)
+	}
+	if nth < 1 {
+		nth = 1
+	}
+	if err := dl.exec(ctx, "BEGIN :res := DBMS_LOB.INSTR(:lob, :pattern, :offset, :nth); END;",
+		sql.Named("res", sql.Out{Dest: &res}), sql.Named("pattern", pat),
+		sql.Named("offset", offset+1), sql.Named("nth", nth),
+	); err != nil {
+		return -1, fmt.Errorf("instr: %w", err)
+	}
+	return res.Int64 - 1, nil
+}
+
+// Substr returns amount bytes (characters for CLOBs) from the LOB, starting at the 0-based offset,
+// read on the server (DBMS_LOB.SUBSTR).
+//
+// The amount is limited to 32767 bytes by PL/SQL.
+func (dl *DirectLob) Substr(ctx context.Context, offset, amount int64) ([]byte, error) {
+	const qry = "BEGIN :res := DBMS_LOB.SUBSTR(:lob, :amount, :offset); END;"
+	var err error
+	if dl.checkIsClob() {
+		var res string
+		if err = dl.exec(ctx, qry,
+			sql.Named("res", sql.Out{Dest: &res}),
+			sql.Named("amount", amount), sql.Named("offset", offset+1),
+		); err == nil {
+			return []byte(res), nil
+		}
+	} else {
+		var res []byte
+		if err = dl.exec(ctx, qry,
+			sql.Named("res", sql.Out{Dest: &res}),
+			sql.Named("amount", amount), sql.Named("offset", offset+1),
+		); err == nil {
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("substr: %w", err)
+}
+
+// Erase amount bytes (characters for CLOBs) of the LOB, starting at the 0-based offset,
+// on the server (DBMS_LOB.ERASE): BLOBs are filled with zero bytes, CLOBs with spaces.
+//
+// Returns the number of bytes (characters) actually erased.
+func (dl *DirectLob) Erase(ctx context.Context, offset, amount int64) (int64, error) {
+	if err := dl.exec(ctx, "BEGIN DBMS_LOB.ERASE(:lob, :amount, :offset); END;",
+		sql.Named("amount", sql.Out{Dest: &amount, In: true}), sql.Named("offset", offset+1),
+	); err != nil {
+		return amount, fmt.Errorf("erase: %w", err)
+	}
+	return amount, nil
+}
+
+// checkIsClob sets isClob from the LOB's type, and returns it.
+func (dl *DirectLob) checkIsClob() bool {
+	var typ C.dpiOracleTypeNum
+	if dl.dpiLob != nil && dl.drv.checkExec(func() C.int { return C.dpiLob_getType(dl.dpiLob, &typ) }) == nil {
+		dl.isClob = typ == C.DPI_ORACLE_TYPE_CLOB || typ == C.DPI_ORACLE_TYPE_NCLOB
+	}
+	return dl.isClob
+}
+
+// asLob returns the DirectLob as a Lob, for binding.
+func (dl *DirectLob) asLob() Lob {
+	isClob := dl.checkIsClob()
+	return Lob{Reader: &dpiLobReader{drv: dl.drv, conn: dl.conn, dpiLob: dl.dpiLob, IsClob: isClob}, IsClob: isClob}
+}
+
+// exec executes the PL/SQL block on the LOB's connection, binding the LOB to :lob (IN OUT).
+//
+// The *DirectLob arguments are bound as LOBs.
+func (dl *DirectLob) exec(ctx context.Context, qry string, args ...sql.NamedArg) error {
+	if dl == nil || dl.dpiLob == nil {
+		return errors.New("DirectLob is closed")
+	}
+	if dl.conn == nil {
+		return errors.New("DirectLob has no connection")
+	}
+	self := dl.asLob()
+	nvs := make([]driver.NamedValue, 0, 1+len(args))
+	nvs = append(nvs, driver.NamedValue{Name: "lob", Ordinal: 1, Value: sql.Out{Dest: &self, In: true}})
+	for i, a := range args {
+		v := a.Value
+		if other, ok := v.(*DirectLob); ok {
+			if other == nil || other.dpiLob == nil {
+				return fmt.Errorf("%s: DirectLob is closed", a.Name)
+			}
+			v = other.asLob()
+		}
+		nvs = append(nvs, driver.NamedValue{Name: a.Name, Ordinal: i + 2, Value: v})
+	}
+	stmt, err := dl.conn.PrepareContext(ctx, qry)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.(driver.StmtExecContext).ExecContext(ctx, nvs)
+	return err
+}
+
 func maxI(a, b int) int {
 	if a < b {
 		return b
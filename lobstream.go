@@ -0,0 +1,203 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include "dpiImpl.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// LobStreamOptions are the options of DirectLob.WriteFrom and StreamLOB.
+type LobStreamOptions struct {
+	// Progress is called after each written piece, with the number of bytes written so far.
+	Progress func(written int64)
+	// ChunkSize is the size of the written pieces, rounded up to a multiple of the LOB's chunk size.
+	// Defaults to the smallest multiple of the chunk size which is at least 1MiB.
+	ChunkSize int
+	// IsClob must be set for CLOB and NCLOB columns (used by StreamLOB).
+	IsClob bool
+}
+
+// StreamLOB executes qry, which must return the locator of an (empty) LOB into its last bind variable,
+// such as
+//
+//	INSERT INTO docs (id, content) VALUES (:1, EMPTY_BLOB()) RETURNING content INTO :2
+//
+// and streams r into that LOB with WriteFrom, so even huge uploads use constant memory,
+// and the data is written directly to its place, without a temporary LOB.
+//
+// args are the arguments for all but the last bind variable.
+//
+// It needs a transaction, which must be committed after this: in autocommit mode the row lock
+// would be released before writing the LOB, failing with ORA-22920.
+func StreamLOB(ctx context.Context, tx *sql.Tx, qry string, r io.Reader, opts LobStreamOptions, args ...interface{}) (int64, error) {
+	if tx == nil {
+		return 0, errors.New("StreamLOB needs a transaction")
+	}
+	// The LOB locator is valid only while the statement is open.
+	stmt, err := tx.PrepareContext(ctx, qry)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", qry, err)
+	}
+	defer stmt.Close()
+	lob := Lob{IsClob: opts.IsClob}
+	if _, err = stmt.ExecContext(ctx, append(append(make([]interface{}, 0, len(args)+1), args...), sql.Out{Dest: &lob})...); err != nil {
+		return 0, fmt.Errorf("%s: %w", qry, err)
+	}
+	if lob.Reader == nil {
+		return 0, fmt.Errorf("%s: no LOB locator has been returned", qry)
+	}
+	dl, err := lob.Hijack()
+	if err != nil {
+		return 0, err
+	}
+	// Hold our own reference, as the statement's variable releases its own.
+	if err = dl.drv.checkExec(func() C.int { return C.dpiLob_addRef(dl.dpiLob) }); err != nil {
+		return 0, fmt.Errorf("addRef: %w", err)
+	}
+	dl.owned = true
+	n, err := dl.WriteFrom(ctx, r, opts)
+	if closeErr := dl.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return n, err
+}
+
+// WriteFrom streams r into the LOB from its beginning, in pieces of multiples of the LOB's chunk size,
+// calling opts.Progress after each piece, and checking ctx for cancelation between the pieces.
+//
+// For CLOBs, r must be UTF-8 encoded text.
+//
+// Returns the number of bytes written.
+func (dl *DirectLob) WriteFrom(ctx context.Context, r io.Reader, opts LobStreamOptions) (int64, error) {
+	if dl == nil || dl.dpiLob == nil {
+		return 0, errors.New("DirectLob is closed")
+	}
+	isClob := dl.checkIsClob()
+	pieceSize, err := dl.pieceSize(opts.ChunkSize, isClob)
+	if err != nil {
+		return 0, err
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if !dl.opened {
+		if err := dl.drv.checkExecNoLOT(func() C.int { return C.dpiLob_openResource(dl.dpiLob) }); err != nil {
+			return 0, fmt.Errorf("openResource: %w", err)
+		}
+		defer func() {
+			// Close the resource to update the indexes on the LOB column.
+			_ = C.dpiLob_closeResource(dl.dpiLob)
+		}()
+	}
+
+	buf := make([]byte, pieceSize)
+	// offset is in bytes for BLOBs, in characters (UCS-2 codepoints) for CLOBs
+	var written, offset int64
+	var pending int // incomplete UTF-8 sequence at the end of the previous piece
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		n, readErr := io.ReadFull(r, buf[pending:])
+		n += pending
+		pending = 0
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			readErr = io.EOF
+		} else if readErr != nil {
+			return written, readErr
+		}
+		piece := buf[:n]
+		if isClob && readErr == nil {
+			piece = buf[:n-incompleteUTF8Suffix(buf[:n])]
+			pending = n - len(piece)
+		}
+		if len(piece) != 0 {
+			if err := dl.drv.checkExecNoLOT(func() C.int {
+				return C.dpiLob_writeBytes(dl.dpiLob, C.uint64_t(offset+1), (*C.char)(unsafe.Pointer(&piece[0])), C.uint64_t(len(piece)))
+			}); err != nil {
+				return written, fmt.Errorf("writeBytes(offset=%d, %d): %w", offset, len(piece), err)
+			}
+			written += int64(len(piece))
+			if isClob {
+				offset += int64(ucs2Len(piece))
+			} else {
+				offset += int64(len(piece))
+			}
+			if opts.Progress != nil {
+				opts.Progress(written)
+			}
+		}
+		if readErr == io.EOF {
+			return written, nil
+		}
+		copy(buf, buf[len(piece):n])
+	}
+}
+
+// pieceSize returns the size of the write buffer in bytes,
+// the smallest multiple of the LOB's chunk size which is at least the wanted size (or 1MiB).
+func (dl *DirectLob) pieceSize(wanted int, isClob bool) (int, error) {
+	var chunkSize C.uint32_t
+	if err := dl.drv.checkExec(func() C.int { return C.dpiLob_getChunkSize(dl.dpiLob, &chunkSize) }); err != nil {
+		return 0, fmt.Errorf("getChunkSize: %w", err)
+	}
+	if chunkSize == 0 {
+		chunkSize = 8192
+	}
+	unit := C.uint64_t(chunkSize)
+	if isClob {
+		// The chunk size of CLOBs is in characters.
+		if err := dl.drv.checkExec(func() C.int { return C.dpiLob_getBufferSize(dl.dpiLob, C.uint64_t(chunkSize), &unit) }); err != nil {
+			return 0, fmt.Errorf("getBufferSize(%d): %w", chunkSize, err)
+		}
+	}
+	if wanted <= 0 {
+		wanted = 1 << 20
+	}
+	return (wanted + int(unit) - 1) / int(unit) * int(unit), nil
+}
+
+// incompleteUTF8Suffix returns the length of the incomplete UTF-8 sequence at the end of p.
+func incompleteUTF8Suffix(p []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(p); i++ {
+		c := p[len(p)-i]
+		if c < utf8.RuneSelf {
+			return 0
+		}
+		if utf8.RuneStart(c) {
+			if utf8.FullRune(p[len(p)-i:]) {
+				return 0
+			}
+			return i
+		}
+	}
+	return 0
+}
+
+// ucs2Len returns the length of the UTF-8 encoded p in UCS-2 codepoints, as Oracle counts the characters of CLOBs.
+func ucs2Len(p []byte) int {
+	var n int
+	for len(p) != 0 {
+		r, size := utf8.DecodeRune(p)
+		p = p[size:]
+		n++
+		if r > 0xFFFF {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import "testing"
+
+func TestIncompleteUTF8Suffix(t *testing.T) {
+	full := []byte("aő€😀")
+	for i, want := range []int{0, 0, 1, 0, 1, 2, 0, 1, 2, 3, 0} {
+		if got := incompleteUTF8Suffix(full[:i]); got != want {
+			t.Errorf("%q: got %d, wanted %d", full[:i], got, want)
+		}
+	}
+}
+
+func TestUCS2Len(t *testing.T) {
+	for s, want := range map[string]int{"": 0, "abc": 3, "árvíztűrő": 9, "😀x": 3} {
+		if got := ucs2Len([]byte(s)); got != want {
+			t.Errorf("%q: got %d, wanted %d", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include "dpiImpl.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergeResult is the number of rows inserted and updated by Merge.
+type MergeResult struct {
+	Inserted, Updated int64
+}
+
+// Merge upserts the rows (a slice of structs, or pointers to structs) into the table:
+// the rows with existing keys are updated, the others are inserted.
+//
+// The column names are the field names (uppercased), or the name in the "godror" struct tag,
+// "-" skips the field. NULLs can be given with pointers and sql.Null* types (see Batch).
+// keys are the names of the key columns, used to match the rows.
+// The keys must be unique among the rows: duplicates are rejected, without changing the table.
+//
+// The statements are array-bound MERGEs, with the bind variables CAST to the columns'
+// types, as described by DescribeQuery: one MERGE for the updates, then one for the inserts,
+// to be able to count them. The rows are processed in batches of DefaultBatchLimit.
+//
+// The MERGEs must run in one transaction, so ex must be a *sql.Tx (which the caller commits),
+// or something which can begin one (*sql.DB, *sql.Conn): then Merge begins a transaction,
+// and commits it at the end, or rolls it back on error.
+func Merge(ctx context.Context, ex Execer, table string, keys []string, rows interface{}) (MergeResult, error) {
+	var res MergeResult
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice {
+		return res, fmt.Errorf("rows must be a slice of structs, got %T", rows)
+	}
+	if len(keys) == 0 {
+		return res, errors.New("no key columns")
+	}
+	elemType := rv.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return res, fmt.Errorf("rows must be a slice of structs, got %T", rows)
+	}
+	var names []string
+	var fields []int
+	for i, n := 0, elemType.NumField(); i < n; i++ {
+		f := elemType.Field(i)
+		if f.PkgPath != "" || fieldIsObjectTypeName(f) {
+			continue
+		}
+		nm, _, _ := parseStructTag(f.Tag)
+		if nm == "-" {
+			continue
+		}
+		if nm == "" {
+			nm = strings.ToUpper(f.Name)
+		}
+		names, fields = append(names, nm), append(fields, i)
+	}
+	if len(names) == 0 {
+		return res, fmt.Errorf("%s has no columns", elemType)
+	}
+
+	qry := "SELECT " + strings.Join(names, ", ") + " FROM " + table + " WHERE 1=0"
+	cols, err := DescribeQuery(ctx, ex, qry)
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", qry, err)
+	}
+	if len(cols) != len(names) {
+		return res, fmt.Errorf("%s: got %d columns, wanted %d", qry, len(cols), len(names))
+	}
+	isKey := make([]bool, len(cols))
+	for _, k := range keys {
+		var found bool
+		for i, col := range cols {
+			if found = strings.EqualFold(k, col.Name) || strings.EqualFold(k, names[i]); found {
+				isKey[i] = true
+				break
+			}
+		}
+		if !found {
+			return res, fmt.Errorf("key %q is not among the columns %q", k, names)
+		}
+	}
+	updQry, insQry := mergeQueries(table, cols, isKey)
+
+	if rv.Len() == 0 {
+		return res, nil
+	}
+	if err = checkMergeKeys(rv, isPtr, fields, isKey); err != nil {
+		return res, err
+	}
+	if txer, ok := ex.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+	}); ok {
+		tx, err := txer.BeginTx(ctx, nil)
+		if err != nil {
+			return res, err
+		}
+		defer tx.Rollback()
+		if res, err = mergeRows(ctx, tx, updQry, insQry, rv, isPtr, fields); err != nil {
+			return res, err
+		}
+		return res, tx.Commit()
+	}
+	if _, ok := ex.(*sql.Tx); !ok {
+		return res, fmt.Errorf("merge needs a *sql.Tx, or a *sql.DB or *sql.Conn to begin a transaction, got %T", ex)
+	}
+	return mergeRows(ctx, ex, updQry, insQry, rv, isPtr, fields)
+}
+
+// checkMergeKeys returns an error if a key occurs more than once among the rows:
+// the duplicates would be lost, as the update MERGE runs before the inserts.
+func checkMergeKeys(rv reflect.Value, isPtr bool, fields []int, isKey []bool) error {
+	seen := make(map[string]int, rv.Len())
+	var buf strings.Builder
+	for i, n := 0, rv.Len(); i < n; i++ {
+		elem := rv.Index(i)
+		if isPtr {
+			if elem.IsNil() {
+				return fmt.Errorf("%d. row is nil", i)
+			}
+			elem = elem.Elem()
+		}
+		buf.Reset()
+		for j, f := range fields {
+			if !isKey[j] {
+				continue
+			}
+			v := elem.Field(f)
+			for v.Kind() == reflect.Ptr && !v.IsNil() {
+				v = v.Elem()
+			}
+			if v.Kind() == reflect.Ptr { // nil
+				buf.WriteString("<nil>\x00")
+				continue
+			}
+			fmt.Fprintf(&buf, "%#v\x00", v.Interface())
+		}
+		k := buf.String()
+		if prev, ok := seen[k]; ok {
+			return fmt.Errorf("%d. row has the same key as the %d. row", i, prev)
+		}
+		seen[k] = i
+	}
+	return nil
+}
+
+// mergeRows executes the update and insert MERGEs for the rows, in batches.
+func mergeRows(ctx context.Context, ex Execer, updQry, insQry string, rv reflect.Value, isPtr bool, fields []int) (MergeResult, error) {
+	var res MergeResult
+	var rc rowCollector
+	values := make([]interface{}, len(fields))
+	for start := 0; start < rv.Len(); start += DefaultBatchLimit {
+		rc.Reset()
+		for i, end := start, minI(start+DefaultBatchLimit, rv.Len()); i < end; i++ {
+			elem := rv.Index(i)
+			if isPtr {
+				if elem.IsNil() {
+					return res, fmt.Errorf("%d. row is nil", i)
+				}
+				elem = elem.Elem()
+			}
+			for j, f := range fields {
+				values[j] = elem.Field(f).Interface()
+			}
+			if err := rc.add(values); err != nil {
+				return res, fmt.Errorf("%d. row: %w", i, err)
+			}
+		}
+		args := rc.Values()
+		if updQry != "" {
+			result, err := ex.ExecContext(ctx, updQry, args...)
+			if err != nil {
+				return res, fmt.Errorf("%s: %w", updQry, err)
+			}
+			n, _ := result.RowsAffected()
+			res.Updated += n
+		}
+		result, err := ex.ExecContext(ctx, insQry, args...)
+		if err != nil {
+			return res, fmt.Errorf("%s: %w", insQry, err)
+		}
+		n, _ := result.RowsAffected()
+		res.Inserted += n
+	}
+	return res, nil
+}
+
+// mergeQueries returns the MERGE statements for updating (empty if all the columns are keys)
+// and for inserting the rows.
+func mergeQueries(table string, cols []QueryColumn, isKey []bool) (updQry, insQry string) {
+	var using, on, set, insCols, insVals strings.Builder
+	using.WriteString("SELECT ")
+	for i, col := range cols {
+		nm := `"` + col.Name + `"`
+		if i != 0 {
+			using.WriteString(", ")
+			insCols.WriteString(", ")
+			insVals.WriteString(", ")
+		}
+		if typ := col.castType(); typ != "" {
+			fmt.Fprintf(&using, "CAST(:%d AS %s) AS %s", i+1, typ, nm)
+		} else {
+			fmt.Fprintf(&using, ":%d AS %s", i+1, nm)
+		}
+		insCols.WriteString(nm)
+		insVals.WriteString("S." + nm)
+		if isKey[i] {
+			if on.Len() != 0 {
+				on.WriteString(" AND ")
+			}
+			on.WriteString("T." + nm + " = S." + nm)
+		} else {
+			if set.Len() != 0 {
+				set.WriteString(", ")
+			}
+			set.WriteString("T." + nm + " = S." + nm)
+		}
+	}
+	using.WriteString(" FROM DUAL")
+	prefix := "MERGE INTO " + table + " T USING (" + using.String() + ") S ON (" + on.String() + ")"
+	if set.Len() != 0 {
+		updQry = prefix + " WHEN MATCHED THEN UPDATE SET " + set.String()
+	}
+	insQry = prefix + " WHEN NOT MATCHED THEN INSERT (" + insCols.String() + ") VALUES (" + insVals.String() + ")"
+	return updQry, insQry
+}
+
+// castType returns the SQL type for CASTing a bind variable to the column's type,
+// or the empty string if no CAST is needed (or possible).
+func (qc QueryColumn) castType() string {
+	length := func(unit string) string {
+		if qc.CharLength > 0 {
+			return fmt.Sprintf("(%d%s)", qc.CharLength, unit)
+		}
+		return fmt.Sprintf("(%d)", qc.Length)
+	}
+	switch C.dpiOracleTypeNum(qc.Type) {
+	case C.DPI_ORACLE_TYPE_VARCHAR:
+		return "VARCHAR2" + length(" CHAR")
+	case C.DPI_ORACLE_TYPE_NVARCHAR:
+		return "NVARCHAR2" + length("")
+	case C.DPI_ORACLE_TYPE_CHAR:
+		return "CHAR" + length(" CHAR")
+	case C.DPI_ORACLE_TYPE_NCHAR:
+		return "NCHAR" + length("")
+	case C.DPI_ORACLE_TYPE_NUMBER:
+		if qc.Precision > 0 && qc.Scale != -127 {
+			return fmt.Sprintf("NUMBER(%d,%d)", qc.Precision, qc.Scale)
+		}
+		return "NUMBER"
+	case C.DPI_ORACLE_TYPE_NATIVE_FLOAT:
+		return "BINARY_FLOAT"
+	case C.DPI_ORACLE_TYPE_NATIVE_DOUBLE:
+		return "BINARY_DOUBLE"
+	case C.DPI_ORACLE_TYPE_DATE:
+		return "DATE"
+	case C.DPI_ORACLE_TYPE_TIMESTAMP:
+		return "TIMESTAMP(9)"
+	case C.DPI_ORACLE_TYPE_TIMESTAMP_TZ:
+		return "TIMESTAMP(9) WITH TIME ZONE"
+	case C.DPI_ORACLE_TYPE_TIMESTAMP_LTZ:
+		return "TIMESTAMP(9) WITH LOCAL TIME ZONE"
+	case C.DPI_ORACLE_TYPE_RAW:
+		return fmt.Sprintf("RAW(%d)", qc.Length)
+	case C.DPI_ORACLE_TYPE_INTERVAL_DS:
+		return "INTERVAL DAY(9) TO SECOND(9)"
+	default:
+		return ""
+	}
+}
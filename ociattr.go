@@ -0,0 +1,249 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+/*
+#include <stdlib.h>
+#include "dpiImpl.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// OCIHandleType is the type of the OCI handle of a connection, for the OCI attributes.
+type OCIHandleType uint32
+
+// The OCI handle types of a connection.
+const (
+	OCIHandleService = OCIHandleType(C.DPI_OCI_HTYPE_SVCCTX)
+	OCIHandleServer  = OCIHandleType(C.DPI_OCI_HTYPE_SERVER)
+	OCIHandleSession = OCIHandleType(C.DPI_OCI_HTYPE_SESSION)
+)
+
+// OCIAttrType is the type of the value of an OCI attribute, as documented by Oracle.
+type OCIAttrType uint8
+
+// The types of the OCI attribute values, and the Go types they are returned as.
+const (
+	OCIAttrUint8  = OCIAttrType(iota + 1) // ub1 as uint8
+	OCIAttrUint16                         // ub2 as uint16
+	OCIAttrUint32                         // ub4 as uint32
+	OCIAttrUint64                         // ub8 as uint64
+	OCIAttrBool                           // boolean as bool
+	OCIAttrText                           // oratext* as string
+	OCIAttrBytes                          // ub1* as []byte
+)
+
+// OCIAttrStmt is implemented by the statements prepared by Conn.PrepareContext, for getting and setting
+// the OCI attributes of the statement handle.
+//
+// WARNING: use only as directed by Oracle - these are escape hatches for the attributes
+// not modeled by the driver.
+type OCIAttrStmt interface {
+	GetOCIAttr(attr uint32, typ OCIAttrType) (interface{}, error)
+	SetOCIAttr(attr uint32, value interface{}) error
+}
+
+var _ OCIAttrStmt = (*statement)(nil)
+
+// OCIAttrConn is implemented by the connections (see Raw), for getting and setting
+// the OCI attributes of the connection's handles:
+//
+//	godror.Raw(ctx, db, func(c godror.Conn) error {
+//		v, err := c.(godror.OCIAttrConn).GetOCIAttr(godror.OCIHandleService, attr, godror.OCIAttrUint32)
+//		...
+//	})
+//
+// WARNING: use only as directed by Oracle - these are escape hatches for the attributes
+// not modeled by the driver.
+type OCIAttrConn interface {
+	GetOCIAttr(handleType OCIHandleType, attr uint32, typ OCIAttrType) (interface{}, error)
+	SetOCIAttr(handleType OCIHandleType, attr uint32, value interface{}) error
+}
+
+var _ OCIAttrConn = (*conn)(nil)
+
+// StmtOCIAttr is an option to set the OCI attribute of the statement handle before the execution.
+// See OCIAttrStmt.SetOCIAttr for the types of value.
+//
+// The attribute is not reset after the execution: it stays on the statement handle,
+// which the statement cache hands out to every later prepare of the same SQL text on the session.
+// Use it with DeleteFromCache if the attribute must not outlive the call.
+//
+// Use it "naked", without sql.Named!
+func StmtOCIAttr(attr uint32, value interface{}) Option {
+	return func(o *stmtOptions) { o.ociAttrs = append(o.ociAttrs, ociAttr{attr: attr, value: value}) }
+}
+
+type ociAttr struct {
+	value interface{}
+	attr  uint32
+}
+
+// GetOCIAttr returns the OCI attribute of the connection's handle (of handleType), as typ.
+//
+// WARNING: use only as directed by Oracle!
+func (c *conn) GetOCIAttr(handleType OCIHandleType, attr uint32, typ OCIAttrType) (interface{}, error) {
+	if c == nil || c.dpiConn == nil {
+		return nil, errors.New("connection is closed")
+	}
+	var value C.dpiDataBuffer
+	var length C.uint32_t
+	if err := c.checkExec(func() C.int {
+		return C.dpiConn_getOciAttr(c.dpiConn, C.uint32_t(handleType), C.uint32_t(attr), &value, &length)
+	}); err != nil {
+		return nil, fmt.Errorf("getOciAttr(%d, %d): %w", handleType, attr, err)
+	}
+	return decodeOCIAttr(&value, length, typ)
+}
+
+// SetOCIAttr sets the OCI attribute of the connection's handle (of handleType).
+// The value must be uint8, uint16, uint32, uint64, bool, string or []byte,
+// matching the attribute's type.
+//
+// WARNING: use only as directed by Oracle!
+func (c *conn) SetOCIAttr(handleType OCIHandleType, attr uint32, value interface{}) error {
+	if c == nil || c.dpiConn == nil {
+		return errors.New("connection is closed")
+	}
+	p, length, free, err := encodeOCIAttr(value)
+	if err != nil {
+		return err
+	}
+	defer free()
+	if err := c.checkExec(func() C.int {
+		return C.dpiConn_setOciAttr(c.dpiConn, C.uint32_t(handleType), C.uint32_t(attr), p, length)
+	}); err != nil {
+		return fmt.Errorf("setOciAttr(%d, %d, %v): %w", handleType, attr, value, err)
+	}
+	return nil
+}
+
+// GetOCIAttr returns the OCI attribute of the statement handle, as typ.
+//
+// WARNING: use only as directed by Oracle!
+func (st *statement) GetOCIAttr(attr uint32, typ OCIAttrType) (interface{}, error) {
+	if st == nil || st.dpiStmt == nil {
+		return nil, errors.New("statement is closed")
+	}
+	var value C.dpiDataBuffer
+	var length C.uint32_t
+	if err := st.checkExec(func() C.int {
+		return C.dpiStmt_getOciAttr(st.dpiStmt, C.uint32_t(attr), &value, &length)
+	}); err != nil {
+		return nil, fmt.Errorf("getOciAttr(%d): %w", attr, err)
+	}
+	return decodeOCIAttr(&value, length, typ)
+}
+
+// SetOCIAttr sets the OCI attribute of the statement handle.
+// The value must be uint8, uint16, uint32, uint64, bool, string or []byte,
+// matching the attribute's type.
+//
+// The attribute stays on the handle, which is reused from the statement cache
+// by the later prepares of the same SQL text (see DeleteFromCache).
+//
+// WARNING: use only as directed by Oracle!
+func (st *statement) SetOCIAttr(attr uint32, value interface{}) error {
+	if st == nil || st.dpiStmt == nil {
+		return errors.New("statement is closed")
+	}
+	p, length, free, err := encodeOCIAttr(value)
+	if err != nil {
+		return err
+	}
+	defer free()
+	if err := st.checkExec(func() C.int {
+		return C.dpiStmt_setOciAttr(st.dpiStmt, C.uint32_t(attr), p, length)
+	}); err != nil {
+		return fmt.Errorf("setOciAttr(%d, %v): %w", attr, value, err)
+	}
+	return nil
+}
+
+// setOCIAttrs sets the OCI attributes given with StmtOCIAttr.
+func (st *statement) setOCIAttrs() error {
+	attrs := st.stmtOptions.ociAttrs
+	if len(attrs) == 0 {
+		return nil
+	}
+	st.stmtOptions.ociAttrs = nil
+	for _, a := range attrs {
+		if err := st.SetOCIAttr(a.attr, a.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeOCIAttr decodes the value got by dpi*_getOciAttr: the scalars are in the buffer,
+// the texts and bytes are pointed to by it.
+func decodeOCIAttr(value *C.dpiDataBuffer, length C.uint32_t, typ OCIAttrType) (interface{}, error) {
+	p := unsafe.Pointer(value)
+	switch typ {
+	case OCIAttrUint8:
+		return uint8(*(*C.uint8_t)(p)), nil
+	case OCIAttrUint16:
+		return uint16(*(*C.uint16_t)(p)), nil
+	case OCIAttrUint32:
+		return uint32(*(*C.uint32_t)(p)), nil
+	case OCIAttrUint64:
+		return uint64(*(*C.uint64_t)(p)), nil
+	case OCIAttrBool:
+		return *(*C.int)(p) != 0, nil
+	case OCIAttrText:
+		s := *(**C.char)(p)
+		if s == nil || length == 0 {
+			return "", nil
+		}
+		return C.GoStringN(s, C.int(length)), nil
+	case OCIAttrBytes:
+		b := *(*unsafe.Pointer)(p)
+		if b == nil || length == 0 {
+			return []byte(nil), nil
+		}
+		return C.GoBytes(b, C.int(length)), nil
+	default:
+		return nil, fmt.Errorf("unknown OCI attribute type %d", typ)
+	}
+}
+
+// encodeOCIAttr returns the C pointer and length of the value for dpi*_setOciAttr,
+// and the function to free the pointer.
+func encodeOCIAttr(value interface{}) (unsafe.Pointer, C.uint32_t, func(), error) {
+	var p unsafe.Pointer
+	var length C.uint32_t
+	switch x := value.(type) {
+	case uint8:
+		p, length = C.malloc(C.sizeof_uint8_t), C.sizeof_uint8_t
+		*(*C.uint8_t)(p) = C.uint8_t(x)
+	case uint16:
+		p, length = C.malloc(C.sizeof_uint16_t), C.sizeof_uint16_t
+		*(*C.uint16_t)(p) = C.uint16_t(x)
+	case uint32:
+		p, length = C.malloc(C.sizeof_uint32_t), C.sizeof_uint32_t
+		*(*C.uint32_t)(p) = C.uint32_t(x)
+	case uint64:
+		p, length = C.malloc(C.sizeof_uint64_t), C.sizeof_uint64_t
+		*(*C.uint64_t)(p) = C.uint64_t(x)
+	case bool:
+		p, length = C.malloc(C.sizeof_int), C.sizeof_int
+		*(*C.int)(p) = 0
+		if x {
+			*(*C.int)(p) = 1
+		}
+	case string:
+		p, length = unsafe.Pointer(C.CString(x)), C.uint32_t(len(x))
+	case []byte:
+		p, length = C.CBytes(x), C.uint32_t(len(x))
+	default:
+		return nil, 0, nil, fmt.Errorf("unsupported OCI attribute value type %T", value)
+	}
+	return p, length, func() { C.free(p) }, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"strconv"
+	"strings"
+)
+
+// OraErrKind is a kind of Oracle errors, to be used with errors.Is:
+//
+//	if errors.Is(err, godror.ErrUniqueViolation) { ... }
+//
+// The error codes of the kinds are in the catalogue of ClassifyError.
+type OraErrKind struct {
+	name    string
+	dpiCode string // DPI-xxxx error (code 0) prefix
+}
+
+func (k *OraErrKind) Error() string { return k.name }
+
+// The kinds of the common Oracle errors.
+var (
+	ErrUniqueViolation     = &OraErrKind{name: "unique constraint violated"}
+	ErrForeignKeyViolation = &OraErrKind{name: "integrity constraint violated"}
+	ErrCheckViolation      = &OraErrKind{name: "check constraint violated"}
+	ErrNotNullViolation    = &OraErrKind{name: "NULL not allowed"}
+	ErrValueTooLarge       = &OraErrKind{name: "value too large"}
+	ErrDeadlock            = &OraErrKind{name: "deadlock detected"}
+	ErrResourceBusy        = &OraErrKind{name: "resource busy"}
+	ErrSnapshotTooOld      = &OraErrKind{name: "snapshot too old"}
+	ErrCanceled            = &OraErrKind{name: "user requested cancel"}
+	ErrTimeout             = &OraErrKind{name: "call timed out", dpiCode: "DPI-1067:"}
+	ErrObjectNotExist      = &OraErrKind{name: "object does not exist"}
+)
+
+// Is reports whether the error is of the given kind (OraErrKind), for errors.Is.
+func (oe *OraErr) Is(target error) bool {
+	k, ok := target.(*OraErrKind)
+	if !ok || oe == nil {
+		return false
+	}
+	if c, ok := oraErrCodes[oe.code]; ok && c.kind == k {
+		return true
+	}
+	return oe.code == 0 && k.dpiCode != "" && strings.Contains(oe.message, k.dpiCode)
+}
+
+// OraErrDetails are the names of the offending objects, parsed from the error message.
+type OraErrDetails struct {
+	// Constraint is the constraint name, with its owner, as in the message (such as SCOTT.PK_EMP).
+	Constraint string
+	// Schema, Table and Column are the offending column's (or table's) names.
+	Schema, Table, Column string
+	// Columns are the columns of the violated unique constraint (since Oracle 23).
+	Columns []string
+	// ActualLength and MaxLength are from ORA-12899 (value too large).
+	ActualLength, MaxLength int
+}
+
+// Details parses the names of the constraint, table, column from the error message.
+// The fields not found in the message are empty.
+func (oe *OraErr) Details() OraErrDetails {
+	var d OraErrDetails
+	if oe == nil {
+		return d
+	}
+	msg := oe.message
+	switch oe.code {
+	case 1, 2290, 2291, 2292:
+		// unique constraint (SCOTT.PK_EMP) violated [on table SCOTT.EMP columns (EMPNO)]
+		if _, rest, ok := stringsCut(msg, "("); ok {
+			d.Constraint, _, _ = stringsCut(rest, ")")
+		}
+		if _, rest, ok := stringsCut(msg, " on table "); ok {
+			tbl, _, _ := stringsCut(rest, " ")
+			d.Schema, d.Table = splitSchema(tbl)
+		}
+		if _, rest, ok := stringsCut(msg, " columns ("); ok {
+			cols, _, _ := stringsCut(rest, ")")
+			for _, c := range strings.Split(cols, ",") {
+				d.Columns = append(d.Columns, unquoteIdent(strings.TrimSpace(c)))
+			}
+		}
+	case 1400, 1407:
+		// cannot insert NULL into ("SCOTT"."EMP"."ENAME"), cannot update ("SCOTT"."EMP"."ENAME") to NULL
+		if _, rest, ok := stringsCut(msg, "("); ok {
+			ident, _, _ := stringsCut(rest, ")")
+			d.Schema, d.Table, d.Column = splitColumn(ident)
+		}
+	case 12899:
+		// value too large for column "SCOTT"."EMP"."ENAME" (actual: 20, maximum: 10)
+		if _, rest, ok := stringsCut(msg, "column "); ok {
+			ident, lengths, _ := stringsCut(rest, " (")
+			d.Schema, d.Table, d.Column = splitColumn(ident)
+			if _, s, ok := stringsCut(lengths, "actual: "); ok {
+				s, _, _ = stringsCut(s, ",")
+				d.ActualLength, _ = strconv.Atoi(s)
+			}
+			if _, s, ok := stringsCut(lengths, "maximum: "); ok {
+				s, _, _ = stringsCut(s, ")")
+				d.MaxLength, _ = strconv.Atoi(s)
+			}
+		}
+	case 942, 2289, 4043, 4080, 1418:
+		// table or view "SCOTT"."X" does not exist (since Oracle 23), object X does not exist
+		if i := strings.Index(msg, " does not exist"); i >= 0 {
+			fields := strings.Fields(msg[:i])
+			if len(fields) > 1 {
+				if last := fields[len(fields)-1]; strings.HasPrefix(last, `"`) || oe.code == 4043 {
+					d.Schema, d.Table = splitSchema(last)
+				}
+			}
+		}
+	}
+	return d
+}
+
+// splitColumn splits "SCHEMA"."TABLE"."COLUMN".
+func splitColumn(ident string) (schema, table, column string) {
+	parts := splitIdent(ident)
+	switch len(parts) {
+	case 1:
+		return "", "", parts[0]
+	case 2:
+		return "", parts[0], parts[1]
+	default:
+		return parts[len(parts)-3], parts[len(parts)-2], parts[len(parts)-1]
+	}
+}
+
+// splitSchema splits "SCHEMA"."TABLE".
+func splitSchema(ident string) (schema, name string) {
+	parts := splitIdent(ident)
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
+
+// splitIdent splits the dot-separated, maybe double-quoted parts of the identifier.
+func splitIdent(ident string) []string {
+	var parts []string
+	for ident != "" {
+		var part string
+		if strings.HasPrefix(ident, `"`) {
+			var ok bool
+			if part, ident, ok = stringsCut(ident[1:], `"`); !ok {
+				return append(parts, part)
+			}
+			ident = strings.TrimPrefix(ident, ".")
+		} else {
+			part, ident, _ = stringsCut(ident, ".")
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func unquoteIdent(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOraErrKind(t *testing.T) {
+	err := fmt.Errorf("insert: %w", &OraErr{code: 1, message: "unique constraint (SCOTT.PK_EMP) violated"})
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("%v is not ErrUniqueViolation", err)
+	}
+	if errors.Is(err, ErrDeadlock) {
+		t.Errorf("%v is ErrDeadlock", err)
+	}
+	if err = (&OraErr{message: "DPI-1067: call timeout of 100 ms exceeded with ORA-3156"}); !errors.Is(err, ErrTimeout) {
+		t.Errorf("%v is not ErrTimeout", err)
+	}
+	if errors.Is(context.Canceled, ErrCanceled) {
+		t.Error("context.Canceled is ErrCanceled")
+	}
+	for _, k := range []*OraErrKind{
+		ErrUniqueViolation, ErrForeignKeyViolation, ErrCheckViolation, ErrNotNullViolation, ErrValueTooLarge,
+		ErrDeadlock, ErrResourceBusy, ErrSnapshotTooOld, ErrCanceled, ErrTimeout, ErrObjectNotExist,
+	} {
+		var found bool
+		for code, c := range oraErrCodes {
+			if c.kind != k {
+				continue
+			}
+			found = true
+			if oe := (&OraErr{code: code}); !errors.Is(oe, k) {
+				t.Errorf("ORA-%05d is not %v", code, k)
+			}
+		}
+		if !found {
+			t.Errorf("no codes for %v", k)
+		}
+	}
+}
+
+func TestOraErrDetails(t *testing.T) {
+	for _, tc := range []struct {
+		err  *OraErr
+		want OraErrDetails
+	}{
+		{&OraErr{code: 1, message: "unique constraint (SCOTT.PK_EMP) violated"},
+			OraErrDetails{Constraint: "SCOTT.PK_EMP"}},
+		{&OraErr{code: 1, message: "unique constraint (SCOTT.PK_EMP) violated on table SCOTT.EMP columns (EMPNO, \"Ename\")"},
+			OraErrDetails{Constraint: "SCOTT.PK_EMP", Schema: "SCOTT", Table: "EMP", Columns: []string{"EMPNO", "Ename"}}},
+		{&OraErr{code: 2291, message: "integrity constraint (SCOTT.FK_DEPTNO) violated - parent key not found"},
+			OraErrDetails{Constraint: "SCOTT.FK_DEPTNO"}},
+		{&OraErr{code: 1400, message: `cannot insert NULL into ("SCOTT"."EMP"."ENAME")`},
+			OraErrDetails{Schema: "SCOTT", Table: "EMP", Column: "ENAME"}},
+		{&OraErr{code: 1407, message: `cannot update ("SCOTT"."EMP"."ENAME") to NULL`},
+			OraErrDetails{Schema: "SCOTT", Table: "EMP", Column: "ENAME"}},
+		{&OraErr{code: 12899, message: `value too large for column "SCOTT"."EMP"."ENAME" (actual: 20, maximum: 10)`},
+			OraErrDetails{Schema: "SCOTT", Table: "EMP", Column: "ENAME", ActualLength: 20, MaxLength: 10}},
+		{&OraErr{code: 942, message: "table or view does not exist"},
+			OraErrDetails{}},
+		{&OraErr{code: 942, message: `table or view "SCOTT"."NOPE" does not exist`},
+			OraErrDetails{Schema: "SCOTT", Table: "NOPE"}},
+		{&OraErr{code: 4043, message: "object NOPE does not exist"},
+			OraErrDetails{Table: "NOPE"}},
+	} {
+		if got := tc.err.Details(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: got %+v, wanted %+v", tc.err, got, tc.want)
+		}
+	}
+}
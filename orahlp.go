@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/godror/godror/slog"
+	"github.com/godror/godror/sqllex"
 )
 
 // Number as string
@@ -289,7 +290,9 @@ func (n *Number) UnmarshalJSON(p []byte) error {
 type QueryColumn struct {
 	Name                           string
 	Type, Length, Precision, Scale int
-	Nullable                       bool
+	// CharLength is the length in characters, for character columns.
+	CharLength int
+	Nullable   bool
 	//Schema string
 	//CharsetID, CharsetForm         int
 }
@@ -327,12 +330,13 @@ func DescribeQuery(ctx context.Context, db Execer, qry string) ([]QueryColumn, e
 		cols = make([]QueryColumn, len(r.columns))
 		for i, col := range r.columns {
 			cols[i] = QueryColumn{
-				Name:      col.Name,
-				Type:      int(col.OracleType),
-				Length:    int(col.Size),
-				Precision: int(col.Precision),
-				Scale:     int(col.Scale),
-				Nullable:  col.Nullable,
+				Name:       col.Name,
+				Type:       int(col.OracleType),
+				Length:     int(col.Size),
+				Precision:  int(col.Precision),
+				Scale:      int(col.Scale),
+				CharLength: int(col.SizeInChars),
+				Nullable:   col.Nullable,
 			}
 		}
 		return nil
@@ -398,74 +402,33 @@ func NamedToOrdered(qry string, namedParams map[string]interface{}) (string, []i
 // MapToSlice modifies query for map (:paramname) to :%d placeholders + slice of params.
 //
 // Calls metParam for each parameter met, and returns the slice of their results.
+//
+// The bind variables in string literals (also q'[...]'), quoted identifiers and comments are left intact,
+// just as the positional (:1) and quoted (:"name") bind variables.
 func MapToSlice(qry string, metParam func(string) interface{}) (string, []interface{}) {
 	if metParam == nil {
 		metParam = func(string) interface{} { return nil }
 	}
 	arr := make([]interface{}, 0, 16)
 	var buf bytes.Buffer
-	state, p, last := 0, 0, 0
-	var prev rune
-
-	Add := func(i int) {
-		state = 0
-		if i-p <= 1 { // :=
-			return
-		}
-		arr = append(arr, metParam(qry[p+1:i]))
-		param := fmt.Sprintf(":%d", len(arr))
-		buf.WriteString(qry[last:p])
-		buf.WriteString(param)
-		last = i
-	}
-
-	for i, r := range qry {
-		switch state {
-		case 2:
-			if r == '\n' {
-				state = 0
-			}
-		case 3:
-			if prev == '*' && r == '/' {
-				state = 0
-			}
-		case 4:
-			if r == '\'' {
-				state = 0
-			}
-		case 0:
-			switch r {
-			case '-':
-				if prev == '-' {
-					state = 2
-				}
-			case '*':
-				if prev == '/' {
-					state = 3
-				}
-			case '\'':
-				state = 4
-			case ':':
-				state = 1
-				p = i
-				// An identifier consists of a letter optionally followed by more letters, numerals, dollar signs, underscores, and number signs.
-				// http://docs.oracle.com/cd/B19306_01/appdev.102/b14261/fundamentals.htm#sthref309
-			}
-		case 1:
-			if !('A' <= r && r <= 'Z' || 'a' <= r && r <= 'z' ||
-				(i-p > 1 && ('0' <= r && r <= '9' || r == '$' || r == '_' || r == '#'))) {
-
-				Add(i)
-			}
+	last := 0
+	for lx := sqllex.New(qry); ; {
+		t := lx.Next()
+		if t.Kind == sqllex.EOF {
+			break
 		}
-		prev = r
-	}
-	if state == 1 {
-		Add(len(qry))
+		if t.Kind != sqllex.BindVar || t.Text[1] == '"' || ('0' <= t.Text[1] && t.Text[1] <= '9') {
+			continue
+		}
+		arr = append(arr, metParam(t.BindName()))
+		buf.WriteString(qry[last:t.Offset])
+		fmt.Fprintf(&buf, ":%d", len(arr))
+		last = t.Offset + len(t.Text)
 	}
-	if last <= len(qry)-1 {
-		buf.WriteString(qry[last:])
+	if last == 0 {
+		return qry, arr
 	}
+	buf.WriteString(qry[last:])
 	return buf.String(), arr
 }
 
@@ -579,6 +542,118 @@ func WrapRows(ctx context.Context, q Querier, rset driver.Rows) (*sql.Rows, erro
 	return q.QueryContext(ctx, wrapResultset, rset)
 }
 
+// ExecQuerier is both an Execer and a Querier, such as *sql.Conn or *sql.Tx.
+type ExecQuerier interface {
+	Execer
+	Querier
+}
+
+// ExecImplicitResults executes the PL/SQL block, and returns its implicit result sets
+// (returned with DBMS_SQL.RETURN_RESULT), to be iterated over.
+//
+// The args may contain OUT parameters (sql.Out), which are filled by the time this returns.
+//
+// q must be a single connection (*sql.Conn or *sql.Tx), as the result sets are read on it.
+// The returned ResultSets must be closed.
+func ExecImplicitResults(ctx context.Context, q ExecQuerier, qry string, args ...interface{}) (*ResultSets, error) {
+	var sets []driver.Rows
+	_, err := q.ExecContext(ctx, qry, append([]interface{}{CollectImplicitResults(&sets)}, args...)...)
+	rs := &ResultSets{ctx: ctx, q: q, sets: sets, index: -1}
+	if err != nil {
+		rs.Close()
+		return nil, err
+	}
+	return rs, nil
+}
+
+// ResultSets is an iterator over the implicit result sets of a PL/SQL block.
+//
+//	rs, err := godror.ExecImplicitResults(ctx, conn, qry)
+//	...
+//	defer rs.Close()
+//	for rs.Next() {
+//		rows := rs.Rows()
+//		for rows.Next() { ... }
+//	}
+//	if err := rs.Err(); err != nil { ... }
+type ResultSets struct {
+	ctx   context.Context
+	q     Querier
+	err   error
+	rows  *sql.Rows
+	sets  []driver.Rows
+	index int
+}
+
+// Len returns the number of result sets.
+func (rs *ResultSets) Len() int { return len(rs.sets) }
+
+// Next advances to the next result set, closing the current one.
+func (rs *ResultSets) Next() bool {
+	if rs.rows != nil {
+		rs.rows.Close()
+		rs.rows = nil
+	}
+	if rs.err != nil || rs.index+1 >= len(rs.sets) {
+		return false
+	}
+	rs.index++
+	dr := rs.sets[rs.index]
+	rs.sets[rs.index] = nil
+	if rs.rows, rs.err = WrapRows(rs.ctx, rs.q, dr); rs.err != nil {
+		dr.Close()
+		return false
+	}
+	return true
+}
+
+// Index returns the 0-based index of the current result set.
+func (rs *ResultSets) Index() int { return rs.index }
+
+// Rows returns the current result set.
+func (rs *ResultSets) Rows() *sql.Rows { return rs.rows }
+
+// Columns returns the column names of the i-th result set, or nil for an invalid index.
+func (rs *ResultSets) Columns(i int) []string {
+	if i < 0 || i >= len(rs.sets) {
+		return nil
+	}
+	if i == rs.index && rs.rows != nil {
+		cols, _ := rs.rows.Columns()
+		return cols
+	}
+	if dr := rs.sets[i]; dr != nil {
+		return dr.Columns()
+	}
+	return nil
+}
+
+// Err returns the error encountered during iteration.
+func (rs *ResultSets) Err() error { return rs.err }
+
+// Close closes all the remaining result sets.
+func (rs *ResultSets) Close() error {
+	if rs == nil {
+		return nil
+	}
+	var firstErr error
+	if rs.rows != nil {
+		firstErr = rs.rows.Close()
+		rs.rows = nil
+	}
+	for i, dr := range rs.sets {
+		if dr == nil {
+			continue
+		}
+		rs.sets[i] = nil
+		if err := dr.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	rs.index = len(rs.sets)
+	return firstErr
+}
+
 // Timezone returns the timezone of the connection (database).
 func Timezone(ctx context.Context, ex Execer) (loc *time.Location, err error) {
 	err = Raw(ctx, ex, func(c Conn) error { loc = c.Timezone(); return nil })
@@ -739,10 +814,7 @@ func (c *PooledConn) Close() error {
 
 // ReplaceQuestionPlacholders replaces ? marks with Oracle-supported :%d placeholders.
 //
-// THIS IS JUST A SIMPLE CONVENIENCE FUNCTION, WITHOUT WARRANTIES:
-// - does not handle '?' - mark in string
-// - does not handle --? - mark in line comment
-// - does not handle /*?*/ - mark in block comment
+// The ? marks in string literals, quoted identifiers and comments are left intact.
 func ReplaceQuestionPlacholders(qry string) string {
 	n := strings.Count(qry, "?")
 	if n == 0 {
@@ -753,19 +825,26 @@ func ReplaceQuestionPlacholders(qry string) string {
 		nLog10++
 		x *= 10
 	}
-	//fmt.Println("\n## n:", n, "x:", x, "nLog10:", nLog10)
 	num := make([]byte, 0, nLog10)
 	var buf strings.Builder
 	buf.Grow(len(qry) + n*(nLog10))
 	var idx int64
-	for i := strings.IndexByte(qry, '?'); i >= 0; i = strings.IndexByte(qry, '?') {
-		buf.WriteString(qry[:i])
-		qry = qry[i+1:]
+	last := 0
+	for lx := sqllex.New(qry); ; {
+		t := lx.Next()
+		if t.Kind == sqllex.EOF {
+			break
+		}
+		if !(t.Kind == sqllex.Punct && t.Text == "?") {
+			continue
+		}
+		buf.WriteString(qry[last:t.Offset])
+		last = t.Offset + 1
 		buf.WriteByte(':')
 		idx++
 		num = strconv.AppendInt(num[:0], idx, 10)
 		buf.Write(num)
 	}
-	buf.WriteString(qry)
+	buf.WriteString(qry[last:])
 	return buf.String()
 }
@@ -68,6 +68,12 @@ END;
 `,
 			[]interface{}{"p002#dijkod", "p002#dijkod"},
 		},
+
+		{
+			`BEGIN x := q'[:no]' || ':no' || "A:no" || :yes || :1; END;`,
+			`BEGIN x := q'[:no]' || ':no' || "A:no" || :1 || :1; END;`,
+			[]interface{}{"yes"},
+		},
 	} {
 
 		got, params := godror.MapToSlice(tc.in, func(s string) interface{} { return s })
@@ -0,0 +1,214 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DisplayCursor returns the actual execution plan of the cursor, as formatted by DBMS_XPLAN.DISPLAY_CURSOR.
+//
+// An empty sqlID means the last statement executed by the session, so q must be
+// the *sql.Conn (or *sql.Tx) that executed it. A negative childNumber means all the children.
+// The default format is "ALLSTATS LAST" (the row source statistics need
+// STATISTICS_LEVEL=ALL or the GATHER_PLAN_STATISTICS hint).
+//
+// See CaptureSQLID for getting the SQL_ID of a statement.
+func DisplayCursor(ctx context.Context, q Querier, sqlID string, childNumber int, format string) (string, error) {
+	if format == "" {
+		format = "ALLSTATS LAST"
+	}
+	const qry = "SELECT plan_table_output FROM TABLE(DBMS_XPLAN.DISPLAY_CURSOR(:1, :2, :3))"
+	child := sql.NullInt64{Int64: int64(childNumber), Valid: childNumber >= 0}
+	rows, err := q.QueryContext(ctx, qry, sql.NullString{String: sqlID, Valid: sqlID != ""}, child, format)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", qry, err)
+	}
+	defer rows.Close()
+	var buf strings.Builder
+	for rows.Next() {
+		var line sql.NullString
+		if err = rows.Scan(&line); err != nil {
+			return buf.String(), fmt.Errorf("%s: %w", qry, err)
+		}
+		buf.WriteString(line.String)
+		buf.WriteByte('\n')
+	}
+	if err = rows.Err(); err != nil {
+		return buf.String(), fmt.Errorf("%s: %w", qry, err)
+	}
+	return buf.String(), nil
+}
+
+// PlanStep is a step (row source) of an execution plan, as in PLAN_TABLE.
+type PlanStep struct {
+	Operation, Options                   string
+	ObjectOwner, ObjectName, ObjectAlias string
+	AccessPredicates, FilterPredicates   string
+	Children                             []*PlanStep
+	ID, ParentID, Depth                  int
+	Cost, Cardinality, Bytes             int64
+	CPUCost, IOCost                      int64
+	// Time is the estimated elapsed time.
+	Time time.Duration
+}
+
+// String returns the plan as an indented tree, one step per line.
+func (ps *PlanStep) String() string {
+	var buf strings.Builder
+	ps.write(&buf, 0)
+	return buf.String()
+}
+
+func (ps *PlanStep) write(buf *strings.Builder, indent int) {
+	if ps == nil {
+		return
+	}
+	fmt.Fprintf(buf, "%3d %s%s", ps.ID, strings.Repeat("  ", indent), ps.Operation)
+	if ps.Options != "" {
+		buf.WriteString(" " + ps.Options)
+	}
+	if ps.ObjectName != "" {
+		buf.WriteByte(' ')
+		if ps.ObjectOwner != "" {
+			buf.WriteString(ps.ObjectOwner + ".")
+		}
+		buf.WriteString(ps.ObjectName)
+	}
+	fmt.Fprintf(buf, " (cost=%d rows=%d bytes=%d)\n", ps.Cost, ps.Cardinality, ps.Bytes)
+	for _, c := range ps.Children {
+		c.write(buf, indent+1)
+	}
+}
+
+// ExplainPlan explains the query with EXPLAIN PLAN, and returns the plan as a tree of steps.
+//
+// The plan is read from (and then deleted from) PLAN_TABLE, in the same session:
+// if ex is an *sql.DB, a connection is acquired for this.
+func ExplainPlan(ctx context.Context, ex ExecQuerier, qry string) (*PlanStep, error) {
+	if conner, ok := ex.(interface {
+		Conn(context.Context) (*sql.Conn, error)
+	}); ok {
+		conn, err := conner.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		ex = conn
+	}
+	stmtID := "godror-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	if _, err := ex.ExecContext(ctx, "EXPLAIN PLAN SET STATEMENT_ID = '"+stmtID+"' FOR "+qry); err != nil {
+		return nil, fmt.Errorf("EXPLAIN PLAN FOR %s: %w", qry, err)
+	}
+	defer func() {
+		_, _ = ex.ExecContext(context.Background(), "DELETE FROM plan_table WHERE statement_id = :1", stmtID)
+	}()
+
+	const planQry = `SELECT id, NVL(parent_id, -1), depth, operation, options,
+  object_owner, object_name, object_alias, access_predicates, filter_predicates,
+  cost, cardinality, bytes, cpu_cost, io_cost, time
+  FROM plan_table WHERE statement_id = :1 ORDER BY id`
+	rows, err := ex.QueryContext(ctx, planQry, stmtID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", planQry, err)
+	}
+	defer rows.Close()
+	var steps []*PlanStep
+	for rows.Next() {
+		var ps PlanStep
+		var strs [7]sql.NullString
+		var nums [6]sql.NullInt64
+		if err = rows.Scan(&ps.ID, &ps.ParentID, &ps.Depth,
+			&strs[0], &strs[1], &strs[2], &strs[3], &strs[4], &strs[5], &strs[6],
+			&nums[0], &nums[1], &nums[2], &nums[3], &nums[4], &nums[5],
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", planQry, err)
+		}
+		ps.Operation, ps.Options = strs[0].String, strs[1].String
+		ps.ObjectOwner, ps.ObjectName, ps.ObjectAlias = strs[2].String, strs[3].String, strs[4].String
+		ps.AccessPredicates, ps.FilterPredicates = strs[5].String, strs[6].String
+		ps.Cost, ps.Cardinality, ps.Bytes = nums[0].Int64, nums[1].Int64, nums[2].Int64
+		ps.CPUCost, ps.IOCost = nums[3].Int64, nums[4].Int64
+		ps.Time = time.Duration(nums[5].Int64) * time.Second
+		steps = append(steps, &ps)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", planQry, err)
+	}
+	return buildPlanTree(steps)
+}
+
+// buildPlanTree links the steps (ordered by ID) into a tree, returning the root.
+func buildPlanTree(steps []*PlanStep) (*PlanStep, error) {
+	if len(steps) == 0 {
+		return nil, errors.New("no plan found")
+	}
+	byID := make(map[int]*PlanStep, len(steps))
+	var root *PlanStep
+	for _, ps := range steps {
+		byID[ps.ID] = ps
+		if ps.ParentID < 0 {
+			if root != nil {
+				return nil, fmt.Errorf("more than one root step (%d and %d)", root.ID, ps.ID)
+			}
+			root = ps
+			continue
+		}
+		parent := byID[ps.ParentID]
+		if parent == nil {
+			return nil, fmt.Errorf("parent %d of step %d not found", ps.ParentID, ps.ID)
+		}
+		parent.Children = append(parent.Children, ps)
+	}
+	if root == nil {
+		return nil, errors.New("no root step")
+	}
+	return root, nil
+}
+
+// SQLMonitorFormat is the type of a SQL Monitor report.
+type SQLMonitorFormat string
+
+// The SQL Monitor report types.
+const (
+	SQLMonitorText   = SQLMonitorFormat("TEXT")
+	SQLMonitorHTML   = SQLMonitorFormat("HTML")
+	SQLMonitorActive = SQLMonitorFormat("ACTIVE")
+	SQLMonitorXML    = SQLMonitorFormat("XML")
+)
+
+// SQLMonitorReport returns the SQL Monitor report (DBMS_SQLTUNE.REPORT_SQL_MONITOR) of the statement,
+// or of the last monitored statement if sqlID is empty. The default format is SQLMonitorText.
+//
+// SQL Monitor needs the Tuning Pack license, and monitors the statements running
+// longer than 5 seconds, or with the MONITOR hint.
+func SQLMonitorReport(ctx context.Context, q Querier, sqlID string, format SQLMonitorFormat) (string, error) {
+	if format == "" {
+		format = SQLMonitorText
+	}
+	const qry = "SELECT DBMS_SQLTUNE.REPORT_SQL_MONITOR(sql_id => :1, type => :2, report_level => 'ALL') FROM DUAL"
+	rows, err := q.QueryContext(ctx, qry, sql.NullString{String: sqlID, Valid: sqlID != ""}, string(format))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", qry, err)
+	}
+	defer rows.Close()
+	var report sql.NullString
+	if rows.Next() {
+		if err = rows.Scan(&report); err != nil {
+			return "", fmt.Errorf("%s: %w", qry, err)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return "", fmt.Errorf("%s: %w", qry, err)
+	}
+	return report.String, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import "testing"
+
+func TestBuildPlanTree(t *testing.T) {
+	t.Parallel()
+	steps := []*PlanStep{
+		{ID: 0, ParentID: -1, Operation: "SELECT STATEMENT", Cost: 3, Cardinality: 1},
+		{ID: 1, ParentID: 0, Depth: 1, Operation: "NESTED LOOPS", Cost: 3, Cardinality: 1},
+		{ID: 2, ParentID: 1, Depth: 2, Operation: "TABLE ACCESS", Options: "FULL", ObjectOwner: "SCOTT", ObjectName: "EMP", Cost: 2, Cardinality: 14, Bytes: 518},
+		{ID: 3, ParentID: 1, Depth: 2, Operation: "INDEX", Options: "UNIQUE SCAN", ObjectOwner: "SCOTT", ObjectName: "PK_DEPT", Cardinality: 1},
+	}
+	root, err := buildPlanTree(steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.ID != 0 || len(root.Children) != 1 || len(root.Children[0].Children) != 2 {
+		t.Fatalf("bad tree: %+v", root)
+	}
+	want := `  0 SELECT STATEMENT (cost=3 rows=1 bytes=0)
+  1   NESTED LOOPS (cost=3 rows=1 bytes=0)
+  2     TABLE ACCESS FULL SCOTT.EMP (cost=2 rows=14 bytes=518)
+  3     INDEX UNIQUE SCAN SCOTT.PK_DEPT (cost=0 rows=1 bytes=0)
+`
+	if got := root.String(); got != want {
+		t.Errorf("got\n%s\nwanted\n%s", got, want)
+	}
+
+	if _, err = buildPlanTree(nil); err == nil {
+		t.Error("wanted error for no steps")
+	}
+	if _, err = buildPlanTree([]*PlanStep{{ID: 1, ParentID: 5}}); err == nil {
+		t.Error("wanted error for missing parent")
+	}
+}
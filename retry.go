@@ -0,0 +1,393 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorClass is the class of an error, for deciding whether the failed work can be retried.
+type ErrorClass uint8
+
+const (
+	// ClassUnknown is for errors not in the catalogue - not retryable.
+	ClassUnknown = ErrorClass(iota)
+	// ClassTransient is for temporary failures, such as a full listener or discarded package state.
+	ClassTransient
+	// ClassBadConn is for broken connections (IsBadConn) - retry on a new connection.
+	ClassBadConn
+	// ClassDeadlock is for deadlocks (ORA-00060) - the transaction should be retried.
+	ClassDeadlock
+	// ClassSerialization is for serialization failures (ORA-08177) of SERIALIZABLE transactions.
+	ClassSerialization
+	// ClassResourceBusy is for lock timeouts, such as ORA-00054 (resource busy, NOWAIT).
+	ClassResourceBusy
+	// ClassTimeout is for timed out calls - not retryable, as the time is up.
+	ClassTimeout
+	// ClassCanceled is for canceled calls - not retryable.
+	ClassCanceled
+	// ClassConstraint is for integrity constraint violations - retrying would fail again.
+	ClassConstraint
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ClassTransient:
+		return "transient"
+	case ClassBadConn:
+		return "bad connection"
+	case ClassDeadlock:
+		return "deadlock"
+	case ClassSerialization:
+		return "serialization"
+	case ClassResourceBusy:
+		return "resource busy"
+	case ClassTimeout:
+		return "timeout"
+	case ClassCanceled:
+		return "canceled"
+	case ClassConstraint:
+		return "constraint"
+	default:
+		return "unknown"
+	}
+}
+
+// Retryable reports whether the work failed with an error of this class may succeed if retried.
+func (c ErrorClass) Retryable() bool {
+	switch c {
+	case ClassTransient, ClassBadConn, ClassDeadlock, ClassSerialization, ClassResourceBusy:
+		return true
+	default:
+		return false
+	}
+}
+
+// oraErrCode is the ErrorClass, and the OraErrKind (if any) of an Oracle error code.
+type oraErrCode struct {
+	kind  *OraErrKind
+	class ErrorClass
+}
+
+// oraErrCodes is the catalogue of Oracle error codes.
+var oraErrCodes = map[int]oraErrCode{
+	// Bad connections, by experience (copied from rana/ora):
+	3106:  {class: ClassBadConn}, // fatal two-task communication protocol error
+	12170: {class: ClassBadConn}, // TNS:Connect timeout occurred
+	12528: {class: ClassBadConn}, // TNS:listener: all appropriate instances are blocking new connections
+	12545: {class: ClassBadConn}, // Connect failed because target host or object does not exist
+	// from go-oci8:
+	1033: {class: ClassBadConn}, // ORACLE initialization or shutdown in progress
+	1034: {class: ClassBadConn}, // ORACLE not available
+	// from https://github.com/oracle/odpi/blob/master/src/dpiError.c#L61-L94
+	22:    {class: ClassBadConn}, // invalid session ID; access denied
+	28:    {class: ClassBadConn}, // your session has been killed
+	31:    {class: ClassBadConn}, // your session has been marked for kill
+	45:    {class: ClassBadConn}, // your session has been terminated with no replay
+	378:   {class: ClassBadConn}, // buffer pools cannot be created as specified
+	602:   {class: ClassBadConn}, // internal programming exception
+	603:   {class: ClassBadConn}, // ORACLE server session terminated by fatal error
+	609:   {class: ClassBadConn}, // could not attach to incoming connection
+	1012:  {class: ClassBadConn}, // not logged on
+	1041:  {class: ClassBadConn}, // internal error. hostdef extension doesn't exist
+	1043:  {class: ClassBadConn}, // user side memory corruption
+	1089:  {class: ClassBadConn}, // immediate shutdown or close in progress
+	1092:  {class: ClassBadConn}, // ORACLE instance terminated. Disconnection forced
+	2396:  {class: ClassBadConn}, // exceeded maximum idle time, please connect again
+	3113:  {class: ClassBadConn}, // end-of-file on communication channel
+	3114:  {class: ClassBadConn}, // not connected to ORACLE
+	3122:  {class: ClassBadConn}, // attempt to close ORACLE-side window on user side
+	3135:  {class: ClassBadConn}, // connection lost contact
+	3136:  {class: ClassBadConn}, // inbound connection timed out
+	12153: {class: ClassBadConn}, // TNS:not connected
+	12537: {class: ClassBadConn}, // TNS:connection closed
+	12547: {class: ClassBadConn}, // TNS:lost contact
+	12570: {class: ClassBadConn}, // TNS:packet reader failure
+	12583: {class: ClassBadConn}, // TNS:no reader
+	27146: {class: ClassBadConn}, // post/wait initialization failed
+	28511: {class: ClassBadConn}, // lost RPC connection
+	28547: {class: ClassBadConn}, // connection to server failed, probable Oracle Net admin error
+	56600: {class: ClassBadConn}, // an illegal OCI function call was issued
+
+	18:    {class: ClassTransient},                                // maximum number of sessions exceeded
+	20:    {class: ClassTransient},                                // maximum number of processes exceeded
+	1555:  {class: ClassTransient, kind: ErrSnapshotTooOld},       // snapshot too old
+	4061:  {class: ClassTransient},                                // existing state has been invalidated
+	4065:  {class: ClassTransient},                                // not executed, altered or dropped
+	4068:  {class: ClassTransient},                                // existing state of packages has been discarded
+	12514: {class: ClassTransient},                                // TNS:listener does not currently know of service (during failover)
+	12516: {class: ClassTransient},                                // TNS:listener could not find available handler
+	12519: {class: ClassTransient},                                // TNS:no appropriate service handler found
+	12520: {class: ClassTransient},                                // TNS:listener could not find available handler for requested type of server
+	24457: {class: ClassTransient},                                // OCISessionGet() could not find a free session in the specified timeout period
+	25402: {class: ClassTransient},                                // transaction must roll back (TAF)
+	25408: {class: ClassTransient},                                // can not safely replay call (TAF)
+	60:    {class: ClassDeadlock, kind: ErrDeadlock},              // deadlock detected while waiting for resource
+	4020:  {class: ClassDeadlock, kind: ErrDeadlock},              // deadlock detected while trying to lock object
+	8177:  {class: ClassSerialization},                            // can't serialize access for this transaction
+	54:    {class: ClassResourceBusy, kind: ErrResourceBusy},      // resource busy and acquire with NOWAIT specified or timeout expired
+	4021:  {class: ClassResourceBusy, kind: ErrResourceBusy},      // timeout occurred while waiting to lock object
+	30006: {class: ClassResourceBusy, kind: ErrResourceBusy},      // resource busy; acquire with WAIT timeout expired
+	1013:  {class: ClassCanceled, kind: ErrCanceled},              // user requested cancel of current operation
+	3156:  {class: ClassTimeout, kind: ErrTimeout},                // OCI call timed out
+	24459: {class: ClassTimeout},                                  // OCISessionGet() timed out waiting for pool to create new connections
+	1:     {class: ClassConstraint, kind: ErrUniqueViolation},     // unique constraint violated
+	1400:  {class: ClassConstraint, kind: ErrNotNullViolation},    // cannot insert NULL
+	1407:  {class: ClassConstraint, kind: ErrNotNullViolation},    // cannot update to NULL
+	1438:  {class: ClassConstraint, kind: ErrValueTooLarge},       // value larger than specified precision allowed for this column
+	2290:  {class: ClassConstraint, kind: ErrCheckViolation},      // check constraint violated
+	2291:  {class: ClassConstraint, kind: ErrForeignKeyViolation}, // integrity constraint violated - parent key not found
+	2292:  {class: ClassConstraint, kind: ErrForeignKeyViolation}, // integrity constraint violated - child record found
+	12899: {class: ClassConstraint, kind: ErrValueTooLarge},       // value too large for column
+	942:   {kind: ErrObjectNotExist},                              // table or view does not exist
+	1418:  {kind: ErrObjectNotExist},                              // specified index does not exist
+	2289:  {kind: ErrObjectNotExist},                              // sequence does not exist
+	4043:  {kind: ErrObjectNotExist},                              // object does not exist
+	4080:  {kind: ErrObjectNotExist},                              // trigger does not exist
+}
+
+// ClassifyError returns the ErrorClass of err, based on the catalogue of Oracle error codes.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ClassUnknown
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ClassTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return ClassCanceled
+	}
+	if IsBadConn(err) {
+		return ClassBadConn
+	}
+	var cd interface{ Code() int }
+	if !errors.As(err, &cd) {
+		return ClassUnknown
+	}
+	if c, ok := oraErrCodes[cd.Code()]; ok && c.class != ClassUnknown {
+		return c.class
+	}
+	if cd.Code() == 0 && strings.Contains(err.Error(), "DPI-1067:") { // call timeout
+		return ClassTimeout
+	}
+	return ClassUnknown
+}
+
+// RetryBudget limits the retries of all the calls sharing it, to avoid retry storms
+// when the database is really down: each retry spends a token, and each success earns Ratio tokens,
+// up to Max tokens. A new budget is full.
+type RetryBudget struct {
+	tokens     float64
+	Max, Ratio float64
+	mu         sync.Mutex
+	started    bool
+}
+
+// NewRetryBudget returns a full RetryBudget of max tokens, earning ratio tokens on each success.
+func NewRetryBudget(max, ratio float64) *RetryBudget {
+	return &RetryBudget{Max: max, Ratio: ratio}
+}
+
+// withdraw a token for a retry, reporting whether it was possible.
+func (b *RetryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.started {
+		b.tokens, b.started = b.Max, true
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// deposit Ratio tokens for a success.
+func (b *RetryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.started {
+		b.tokens, b.started = b.Max, true
+	}
+	if b.tokens += b.Ratio; b.tokens > b.Max {
+		b.tokens = b.Max
+	}
+}
+
+// RetryPolicy retries the work failed with a retryable error (see ErrorClass.Retryable),
+// with exponential back-off and jitter.
+//
+// The zero value is usable, with the defaults of DefaultRetryPolicy.
+type RetryPolicy struct {
+	// Classify the errors - ClassifyError if nil.
+	Classify func(error) ErrorClass
+	// OnRetry is called before each retry, with the number of the failed attempt, its error and the delay.
+	OnRetry func(attempt int, err error, delay time.Duration)
+	// Budget, if not nil, limits the retries of all the calls sharing it.
+	Budget *RetryBudget
+	// MaxAttempts is the maximum number of attempts (including the first), 5 if zero.
+	MaxAttempts int
+	// BaseDelay is the delay after the first failure, doubled after each retry, up to MaxDelay.
+	// Defaults to 50ms and 5s.
+	BaseDelay, MaxDelay time.Duration
+	// Jitter is the randomized fraction of the delay (0 - 1), 0.5 if zero. Negative means no jitter.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used by Retry and RetryTx.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: 50 * time.Millisecond, MaxDelay: 5 * time.Second, Jitter: 0.5}
+
+// Retry calls f till it succeeds, or fails with a non-retryable error, using DefaultRetryPolicy.
+//
+// f must be idempotent!
+func Retry(ctx context.Context, f func(context.Context) error) error {
+	return DefaultRetryPolicy.Retry(ctx, f)
+}
+
+// RetryTx calls f in a new transaction, committing it if f succeeds, rolling it back and retrying
+// if f fails with a retryable error, using DefaultRetryPolicy.
+//
+// A failed commit is not retried (see CommitError).
+func RetryTx(ctx context.Context, db TxBeginner, f func(*sql.Tx) error) error {
+	return DefaultRetryPolicy.RetryTx(ctx, db, nil, f)
+}
+
+// TxBeginner is the BeginTx of *sql.DB and *sql.Conn.
+type TxBeginner interface {
+	BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+}
+
+// CommitError is the error of the failed commit of RetryTx.
+//
+// It is not retried, as the outcome of the commit is unknown:
+// the transaction may have been committed, for example if the connection broke.
+type CommitError struct {
+	Err error
+}
+
+func (ce *CommitError) Error() string { return "commit: " + ce.Err.Error() }
+func (ce *CommitError) Unwrap() error { return ce.Err }
+
+// RetryTx calls f in a new transaction (with the options), committing it if f succeeds,
+// rolling it back and retrying if f fails with a retryable error.
+//
+// A failed commit is returned as a *CommitError, without retrying.
+func (p RetryPolicy) RetryTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions, f func(*sql.Tx) error) error {
+	classify := p.Classify
+	if classify == nil {
+		classify = ClassifyError
+	}
+	p.Classify = func(err error) ErrorClass {
+		var ce *CommitError
+		if errors.As(err, &ce) {
+			return ClassUnknown
+		}
+		return classify(err)
+	}
+	return p.Retry(ctx, func(ctx context.Context) error {
+		tx, err := db.BeginTx(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if err = f(tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err = tx.Commit(); err != nil {
+			return &CommitError{Err: err}
+		}
+		return nil
+	})
+}
+
+// Retry calls f till it succeeds, or fails with a non-retryable error,
+// or the attempts, the budget or the context are exhausted.
+//
+// f must be idempotent!
+func (p RetryPolicy) Retry(ctx context.Context, f func(context.Context) error) error {
+	classify := p.Classify
+	if classify == nil {
+		classify = ClassifyError
+	}
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	logger := getLogger(ctx)
+	for attempt := 1; ; attempt++ {
+		err := f(ctx)
+		if err == nil {
+			p.Budget.deposit()
+			return nil
+		}
+		class := classify(err)
+		if !class.Retryable() || attempt >= maxAttempts || ctx.Err() != nil {
+			if attempt > 1 {
+				return fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+			return err
+		}
+		if !p.Budget.withdraw() {
+			return fmt.Errorf("retry budget exhausted after %d attempts: %w", attempt, err)
+		}
+		delay := p.delay(attempt)
+		if logger != nil {
+			logger.Warn("retry", "attempt", attempt, "class", class.String(), "delay", delay, "error", err)
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (after %d attempts: %v)", ctx.Err(), attempt, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns the back-off delay after the failed attempt (counting from 1).
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	base, maxDelay, jitter := p.BaseDelay, p.MaxDelay, p.Jitter
+	if base <= 0 {
+		base = DefaultRetryPolicy.BaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	if jitter == 0 {
+		jitter = DefaultRetryPolicy.Jitter
+	} else if jitter < 0 {
+		jitter = 0
+	} else if jitter > 1 {
+		jitter = 1
+	}
+	d := base
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	if jitter > 0 {
+		d = time.Duration(float64(d) * (1 - jitter + jitter*rand.Float64()))
+	}
+	return d
+}
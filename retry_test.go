@@ -0,0 +1,115 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassifyError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want ErrorClass
+	}{
+		{nil, ClassUnknown},
+		{errors.New("x"), ClassUnknown},
+		{&OraErr{code: 60}, ClassDeadlock},
+		{fmt.Errorf("wrapped: %w", &OraErr{code: 54}), ClassResourceBusy},
+		{&OraErr{code: 1}, ClassConstraint},
+		{&OraErr{code: 3113}, ClassBadConn},
+		{&OraErr{code: 28}, ClassBadConn},
+		{&OraErr{code: 1034}, ClassBadConn},
+		{&OraErr{code: 8177}, ClassSerialization},
+		{&OraErr{code: 4068}, ClassTransient},
+		{&OraErr{message: "DPI-1067: call timeout of 100 ms exceeded"}, ClassTimeout},
+		{context.DeadlineExceeded, ClassTimeout},
+		{fmt.Errorf("x: %w", context.Canceled), ClassCanceled},
+	} {
+		if got := ClassifyError(tc.err); got != tc.want {
+			t.Errorf("%v: got %s, wanted %s", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	ctx := context.Background()
+	p := RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond, Jitter: -1}
+	for i, want := range []time.Duration{1, 2, 4, 4} {
+		if got := p.delay(i + 1); got != want*time.Millisecond {
+			t.Errorf("delay(%d): got %s, wanted %s", i+1, got, want*time.Millisecond)
+		}
+	}
+
+	var n int
+	if err := p.Retry(ctx, func(context.Context) error {
+		if n++; n < 3 {
+			return &OraErr{code: 60}
+		}
+		return nil
+	}); err != nil || n != 3 {
+		t.Errorf("got %d attempts, %+v", n, err)
+	}
+
+	n = 0
+	if err := p.Retry(ctx, func(context.Context) error { n++; return &OraErr{code: 1} }); err == nil || n != 1 {
+		t.Errorf("constraint error: got %d attempts, %+v", n, err)
+	}
+
+	n = 0
+	p.MaxAttempts = 3
+	if err := p.Retry(ctx, func(context.Context) error { n++; return &OraErr{code: 54} }); err == nil || n != 3 {
+		t.Errorf("MaxAttempts: got %d attempts, %+v", n, err)
+	}
+
+	n = 0
+	p.MaxAttempts = 10
+	p.Budget = NewRetryBudget(2, 0.5)
+	if err := p.Retry(ctx, func(context.Context) error { n++; return &OraErr{code: 54} }); err == nil || n != 3 {
+		t.Errorf("Budget: got %d attempts, %+v", n, err)
+	}
+	for i := 0; i < 2; i++ {
+		_ = p.Retry(ctx, func(context.Context) error { return nil })
+	}
+	n = 0
+	if err := p.Retry(ctx, func(context.Context) error { n++; return &OraErr{code: 54} }); err == nil || n != 2 {
+		t.Errorf("refilled Budget: got %d attempts, %+v", n, err)
+	}
+}
+
+func TestRetryTxCommit(t *testing.T) {
+	ctx := context.Background()
+	db := sql.OpenDB(failCommitConnector{})
+	defer db.Close()
+	p := RetryPolicy{BaseDelay: time.Millisecond, Jitter: -1}
+	var n int
+	err := p.RetryTx(ctx, db, nil, func(*sql.Tx) error { n++; return nil })
+	var ce *CommitError
+	if !errors.As(err, &ce) {
+		t.Errorf("got %+v, wanted CommitError", err)
+	}
+	if n != 1 {
+		t.Errorf("failed commit retried: got %d attempts", n)
+	}
+}
+
+// failCommitConnector's transactions fail to commit, with a bad connection error.
+type failCommitConnector struct{}
+
+func (fc failCommitConnector) Connect(context.Context) (driver.Conn, error) { return fc, nil }
+func (fc failCommitConnector) Driver() driver.Driver                        { return nil }
+func (fc failCommitConnector) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+func (fc failCommitConnector) Close() error              { return nil }
+func (fc failCommitConnector) Begin() (driver.Tx, error) { return fc, nil }
+func (fc failCommitConnector) Commit() error             { return &OraErr{code: 3113} }
+func (fc failCommitConnector) Rollback() error           { return nil }
@@ -46,9 +46,13 @@ type rows struct {
 	*statement
 	origSt         *statement
 	nextRs         *C.dpiStmt
+	slowQuery      *slowQuery // for logging the slow queries at Close
 	data           [][]C.dpiData
 	columns        []Column
 	vars           []*C.dpiVar
+	defines        []varInfo
+	rowWidth       int // for AdaptiveFetch
+	growFetchTo    int // for AdaptiveFetch
 	bufferRowIndex C.uint32_t
 	fetched        C.uint32_t
 	fromData       bool
@@ -71,6 +75,12 @@ func (r *rows) Close() error {
 	if r == nil {
 		return nil
 	}
+	if sq := r.slowQuery; sq != nil {
+		r.slowQuery = nil
+		if r.statement != nil && (r.err == nil || r.err == io.EOF) {
+			r.statement.logSlowQuery("Query", *sq)
+		}
+	}
 	vars, st, nextRs := r.vars, r.statement, r.nextRs
 	r.columns, r.vars, r.data, r.statement, r.nextRs = nil, nil, nil, nil, nil
 	fromData := r.fromData
@@ -293,80 +303,23 @@ func (r *rows) Next(dest []driver.Value) error {
 	if len(dest) != len(r.columns) {
 		return fmt.Errorf("column count mismatch: we have %d columns, but given %d destination", len(r.columns), len(dest))
 	}
-	ctx := context.Background()
+	// nil ctx can be present when Next is issued on cursor returned from DB
+	var ctx context.Context
+	if r.statement != nil {
+		ctx = r.statement.ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	logger := getLogger(ctx)
 
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 
 	if r.fetched == 0 {
-		// Start the watchdog only once See issue #113 (https://github.com/godror/godror/issues/113)
-		if ctx := r.statement.ctx; ctx != nil {
-			// nil can be present when Next is issued on cursor returned from DB
-			if r.err = ctx.Err(); r.err != nil {
-				return r.err
-			}
-			if _, hasDeadline := r.statement.ctx.Deadline(); hasDeadline {
-				// handle deadline for dpiStmt_fetchRows. context reused from stmt
-				cleanup, err := r.statement.handleDeadline(ctx)
-				if err != nil {
-					return err
-				}
-				defer cleanup()
-			}
-		}
-
-		var moreRows C.int
-		var start time.Time
-		maxRows := C.uint32_t(r.statement.FetchArraySize())
-		r.statement.Lock()
-		if debugRowsNext {
-			fmt.Printf("fetching max=%d\n", maxRows)
-			start = time.Now()
-		}
-		err := r.statement.checkExecNoLOT(func() C.int {
-			return C.dpiStmt_fetchRows(r.dpiStmt, maxRows, &r.bufferRowIndex, &r.fetched, &moreRows)
-		})
-		failed := err != nil
-		if debugRowsNext {
-			fmt.Printf("failed=%t bri=%d fetched=%d more=%d data=%d cols=%d dur=%s\n", failed, r.bufferRowIndex, r.fetched, moreRows, len(r.data), len(r.columns), time.Since(start))
-		}
-		r.statement.Unlock()
-		if failed {
-			if logger != nil {
-				logger.Error("fetch", "error", err)
-			}
-			_ = r.Close()
-			if strings.Contains(err.Error(), "DPI-1039: statement was already closed") {
-				r.err = io.EOF
-			} else {
-				r.err = fmt.Errorf("Next: %w", err)
-			}
-			return r.err
-		}
-		if logger != nil && logger.Enabled(ctx, slog.LevelDebug) {
-			logger.Debug("fetched", "bri", r.bufferRowIndex, "fetched", r.fetched, "moreRows", moreRows, "len(data)", len(r.data), "cols", len(r.columns))
+		if err := r.fetch(ctx, logger); err != nil {
+			return err
 		}
-		if r.fetched == 0 {
-			_ = r.Close()
-			r.err = io.EOF
-			return r.err
-		}
-		if r.data == nil {
-			r.data = make([][]C.dpiData, len(r.columns))
-			for i := range r.columns {
-				var n C.uint32_t
-				var data *C.dpiData
-				if err = r.statement.checkExecNoLOT(func() C.int {
-					return C.dpiVar_getReturnedData(r.vars[i], 0, &n, &data)
-				}); err != nil {
-					return fmt.Errorf("getReturnedData[%d]: %w", i, err)
-				}
-				r.data[i] = unsafe.Slice(data, n)
-				//fmt.Printf("data %d=%+v\n%+v\n", n, data, r.data[i][0])
-			}
-		}
-
 	}
 	//fmt.Printf("data=%#v\n", r.data)
 
@@ -581,7 +534,7 @@ func (r *rows) Next(dest []driver.Value) error {
 				}
 				continue
 			}
-			rdr := &dpiLobReader{dpiLob: C.dpiData_getLOB(d), drv: r.drv, IsClob: isClob}
+			rdr := &dpiLobReader{dpiLob: C.dpiData_getLOB(d), drv: r.drv, conn: r.conn, ctx: ctx, IsClob: isClob}
 			if isClob && (r.ClobAsString() || !r.LobAsReader()) {
 				sb := stringBuilders.Get()
 				_, err := io.Copy(sb, rdr)
@@ -594,6 +547,14 @@ func (r *rows) Next(dest []driver.Value) error {
 				stringBuilders.Put(sb)
 				continue
 			}
+			if r.inlineLobSize > 0 && typ != C.DPI_ORACLE_TYPE_BFILE { // BFILEs are scanned into BFile
+				if v, ok, err := inlineLob(rdr, r.inlineLobSize); err != nil {
+					return err
+				} else if ok {
+					dest[i] = v
+					continue
+				}
+			}
 			dest[i] = &Lob{Reader: rdr, IsClob: rdr.IsClob}
 
 		case C.DPI_ORACLE_TYPE_STMT, C.DPI_NATIVE_TYPE_STMT:
@@ -753,6 +714,182 @@ func (r *rows) getImplicitResult() {
 	}
 	C.dpiStmt_addRef(r.nextRs)
 }
+
+// getImplicitResults returns all the implicit result sets of the executed statement.
+func (st *statement) getImplicitResults(ctx context.Context) ([]driver.Rows, error) {
+	var sets []driver.Rows
+	for {
+		var dpiStmt *C.dpiStmt
+		if err := st.checkExec(func() C.int { return C.dpiStmt_getImplicitResult(st.dpiStmt, &dpiStmt) }); err != nil {
+			return sets, fmt.Errorf("getImplicitResult: %w", err)
+		}
+		if dpiStmt == nil {
+			return sets, nil
+		}
+		var colCount C.uint32_t
+		if err := st.checkExec(func() C.int { return C.dpiStmt_getNumQueryColumns(dpiStmt, &colCount) }); err != nil {
+			C.dpiStmt_release(dpiStmt)
+			return sets, fmt.Errorf("getNumQueryColumns: %w", err)
+		}
+		st2 := &statement{conn: st.conn, dpiStmt: dpiStmt,
+			stmtOptions: st.stmtOptions, // inherit parent statement's options
+		}
+		st2.implicitResults = nil
+		st2.Lock()
+		r, err := st2.openRows(ctx, int(colCount))
+		st2.Unlock()
+		if err != nil {
+			st2.Close()
+			return sets, err
+		}
+		// the rows owns the only reference from now on, and closes st2 on Close
+		C.dpiStmt_release(dpiStmt)
+		sets = append(sets, r)
+	}
+}
+
+// fetch the next batch of rows into the variables.
+//
+// Observes the query's context on each fetch, and returns io.EOF if no more rows are there.
+func (r *rows) fetch(ctx context.Context, logger *slog.Logger) error {
+	// Observe the query's context on each fetch.
+	if r.err = ctx.Err(); r.err != nil {
+		_ = r.Close()
+		return r.err
+	}
+	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+		// handle deadline for dpiStmt_fetchRows. context reused from stmt
+		cleanup, err := r.statement.handleDeadline(ctx)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
+
+	if r.growFetchTo > 0 {
+		if err := r.growFetchArraySize(); err != nil {
+			_ = r.Close()
+			r.err = fmt.Errorf("Next: %w", err)
+			return r.err
+		}
+	}
+	var moreRows C.int
+	maxRows := C.uint32_t(r.statement.FetchArraySize())
+	c := r.statement.conn
+	done := make(chan struct{})
+	if ctx.Done() != nil && c != nil && !c.params.NoBreakOnContextCancel {
+		// Forcefully BREAK the fetch on context cancelation
+		go func() {
+			select {
+			case <-done:
+			case <-ctx.Done():
+				select {
+				case <-done:
+				default:
+					if logger != nil {
+						logger.Warn("BREAK dpiStmt_fetchRows")
+					}
+					c.Break()
+				}
+			}
+		}()
+	}
+	r.statement.Lock()
+	if debugRowsNext {
+		fmt.Printf("fetching max=%d\n", maxRows)
+	}
+	_, ts := startSpan(ctx, "Fetch", "")
+	start := time.Now()
+	err := r.statement.checkExecNoLOT(func() C.int {
+		return C.dpiStmt_fetchRows(r.dpiStmt, maxRows, &r.bufferRowIndex, &r.fetched, &moreRows)
+	})
+	close(done)
+	ts.end(err, slog.Int64(TraceAttrRowsReturned, int64(r.fetched)))
+	dur := time.Since(start)
+	failed := err != nil
+	if r.slowQuery != nil {
+		r.slowQuery.elapsed += dur
+		r.slowQuery.rows += int64(r.fetched)
+	}
+	if debugRowsNext {
+		fmt.Printf("failed=%t bri=%d fetched=%d more=%d data=%d cols=%d dur=%s\n", failed, r.bufferRowIndex, r.fetched, moreRows, len(r.data), len(r.columns), dur)
+	}
+	r.statement.Unlock()
+	if failed {
+		if logger != nil {
+			logger.Error("fetch", "error", err)
+		}
+		r.slowQuery = nil
+		_ = r.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			r.err = ctxErr
+		} else if strings.Contains(err.Error(), "DPI-1039: statement was already closed") {
+			r.err = io.EOF
+		} else {
+			r.err = fmt.Errorf("Next: %w", err)
+		}
+		return r.err
+	}
+	if logger != nil && logger.Enabled(ctx, slog.LevelDebug) {
+		logger.Debug("fetched", "bri", r.bufferRowIndex, "fetched", r.fetched, "moreRows", moreRows, "len(data)", len(r.data), "cols", len(r.columns))
+	}
+	if r.fetched == 0 {
+		_ = r.Close()
+		r.err = io.EOF
+		return r.err
+	}
+	if r.rowWidth != 0 && moreRows != 0 {
+		r.adaptFetchArraySize(int(maxRows), int(r.fetched), dur)
+	}
+	if r.data == nil {
+		r.data = make([][]C.dpiData, len(r.columns))
+		for i := range r.columns {
+			var n C.uint32_t
+			var data *C.dpiData
+			if err = r.statement.checkExecNoLOT(func() C.int {
+				return C.dpiVar_getReturnedData(r.vars[i], 0, &n, &data)
+			}); err != nil {
+				return fmt.Errorf("getReturnedData[%d]: %w", i, err)
+			}
+			r.data[i] = unsafe.Slice(data, n)
+			//fmt.Printf("data %d=%+v\n%+v\n", n, data, r.data[i][0])
+		}
+	}
+	return nil
+}
+
+// inlineLob reads the whole LOB iff its size is at most maxSize,
+// and returns it as string (for CLOB) or []byte (for BLOB).
+func inlineLob(rdr *dpiLobReader, maxSize int) (interface{}, bool, error) {
+	var size C.uint64_t
+	// the length is prefetched with the locator, so this does not need a round-trip
+	if err := rdr.checkExec(func() C.int { return C.dpiLob_getSize(rdr.dpiLob, &size) }); err != nil {
+		return nil, false, fmt.Errorf("getSize: %w", err)
+	}
+	if size > C.uint64_t(maxSize) {
+		return nil, false, nil
+	}
+	n := int(size)
+	if rdr.IsClob {
+		n *= 4 // size is in characters, at most 4 bytes each in UTF-8
+	}
+	b := make([]byte, n)
+	var off int
+	for off < len(b) {
+		k, err := rdr.read(b[off:])
+		off += k
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+	}
+	if rdr.IsClob {
+		return string(b[:off]), true, nil
+	}
+	return b[:off], true, nil
+}
+
 func (r *rows) HasNextResultSet() bool {
 	if r == nil || r.statement == nil || r.conn == nil {
 		return false
@@ -0,0 +1,481 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/godror/godror/sqllex"
+)
+
+// ScriptStatementKind is the kind of a ScriptStatement.
+type ScriptStatementKind uint8
+
+const (
+	// ScriptSQL is a plain SQL statement (terminated by ";" or "/").
+	ScriptSQL = ScriptStatementKind(iota)
+	// ScriptPLSQL is a PL/SQL block or a stored program unit definition (terminated by "/").
+	ScriptPLSQL
+	// ScriptCommand is a SQL*Plus command (PROMPT, SET, WHENEVER, EXIT...).
+	ScriptCommand
+)
+
+func (k ScriptStatementKind) String() string {
+	switch k {
+	case ScriptSQL:
+		return "SQL"
+	case ScriptPLSQL:
+		return "PL/SQL"
+	case ScriptCommand:
+		return "command"
+	default:
+		return fmt.Sprintf("ScriptStatementKind(%d)", uint8(k))
+	}
+}
+
+// ScriptStatement is one statement of a script, as returned by ParseScript.
+type ScriptStatement struct {
+	// Text is the statement with the &substitution variables replaced,
+	// without the terminating ";" (for SQL) or "/".
+	Text string
+	// Line is the (1-based) line number of the statement's first line in the script.
+	Line int
+	Kind ScriptStatementKind
+}
+
+// ScriptResult is the outcome of one executed statement of ExecScript.
+type ScriptResult struct {
+	Err error
+	ScriptStatement
+	// Output holds the DBMS_OUTPUT of the statement, iff SERVEROUTPUT is ON.
+	Output       string
+	RowsAffected int64
+	Elapsed      time.Duration
+}
+
+// ScriptOptions governs how ExecScript parses and runs the script.
+type ScriptOptions struct {
+	// Defines holds the initial values of &substitution variables.
+	// The keys are case insensitive.
+	Defines map[string]string
+	// Output receives the PROMPT texts and the DBMS_OUTPUT, iff SERVEROUTPUT is ON.
+	Output io.Writer
+	// ExitOnError stops at the first failing statement,
+	// as if the script started with WHENEVER SQLERROR EXIT.
+	ExitOnError bool
+}
+
+// ErrScriptExit is returned by ExecScript (wrapped) when an EXIT or QUIT command
+// stopped the execution of the script.
+var ErrScriptExit = errors.New("script exited")
+
+// ScriptError is returned by ExecScript when a statement failed
+// and WHENEVER SQLERROR EXIT is in effect.
+type ScriptError struct {
+	Err error
+	ScriptStatement
+}
+
+func (se *ScriptError) Error() string {
+	return fmt.Sprintf("line %d: %s: %v", se.Line, se.Text, se.Err)
+}
+func (se *ScriptError) Unwrap() error { return se.Err }
+
+// ScriptConn is what ExecScript needs: executing statements and reading DBMS_OUTPUT on the same session,
+// such as an *sql.Conn or an *sql.Tx.
+type ScriptConn interface {
+	Execer
+	preparer
+}
+
+// ExecScript parses the SQL*Plus-like script and executes its statements one by one,
+// returning the outcome of each executed SQL and PL/SQL statement.
+//
+// SQL statements are terminated by a ";" at the end of a line, or a "/" on a line by itself.
+// PL/SQL blocks (DECLARE, BEGIN, CREATE PROCEDURE/FUNCTION/PACKAGE/TRIGGER/TYPE...)
+// are terminated by a "/" on a line by itself.
+//
+// The supported SQL*Plus commands are
+//
+//	PROMPT text
+//	DEFINE name = value, UNDEFINE name, SET DEFINE ON|OFF|c
+//	SET SERVEROUTPUT ON|OFF (DBMS_OUTPUT is read with ReadDbmsOutput after each statement)
+//	WHENEVER SQLERROR EXIT|CONTINUE
+//	EXIT, QUIT
+//	REMARK
+//
+// other SET, SHOW, SPOOL, COLUMN... commands are ignored.
+//
+// If a statement fails, its ScriptResult.Err is set and the execution goes on,
+// unless WHENEVER SQLERROR EXIT is in effect (or opts.ExitOnError is true) -
+// then the returned error is a *ScriptError.
+//
+// Warning! conn must be one session (*sql.Conn or *sql.Tx), for DBMS_OUTPUT to work.
+func ExecScript(ctx context.Context, conn ScriptConn, script io.Reader, opts ScriptOptions) ([]ScriptResult, error) {
+	stmts, err := ParseScript(script, opts.Defines)
+	if err != nil {
+		return nil, err
+	}
+	logger := getLogger(ctx)
+	results := make([]ScriptResult, 0, len(stmts))
+	exitOnError, serverOutput := opts.ExitOnError, false
+	var buf strings.Builder
+	for _, st := range stmts {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		if st.Kind == ScriptCommand {
+			cmd, rest := scriptCommand(st.Text)
+			switch cmd {
+			case "PROMPT":
+				if opts.Output != nil {
+					if _, err := io.WriteString(opts.Output, rest+"\n"); err != nil {
+						return results, err
+					}
+				}
+			case "SET":
+				name, value := scriptCommand(rest)
+				if name != "SERVEROUTPUT" {
+					continue
+				}
+				value, _ = scriptCommand(value)
+				if serverOutput = value == "ON"; serverOutput {
+					if err := EnableDbmsOutput(ctx, conn); err != nil {
+						return results, &ScriptError{Err: err, ScriptStatement: st}
+					}
+				}
+			case "WHENEVER":
+				what, action := scriptCommand(rest)
+				if what == "SQLERROR" {
+					action, _ = scriptCommand(action)
+					exitOnError = action == "EXIT"
+				}
+			case "EXIT":
+				return results, fmt.Errorf("line %d: %w", st.Line, ErrScriptExit)
+			}
+			continue
+		}
+
+		if logger != nil {
+			logger.Debug("ExecScript", "line", st.Line, "kind", st.Kind, "qry", st.Text)
+		}
+		res := ScriptResult{ScriptStatement: st}
+		start := time.Now()
+		var sr sql.Result
+		if sr, res.Err = conn.ExecContext(ctx, st.Text); res.Err == nil {
+			res.RowsAffected, _ = sr.RowsAffected()
+		}
+		res.Elapsed = time.Since(start)
+		if serverOutput {
+			buf.Reset()
+			if err := ReadDbmsOutput(ctx, &buf, conn); err != nil && res.Err == nil {
+				res.Err = err
+			}
+			res.Output = buf.String()
+			if opts.Output != nil && res.Output != "" {
+				if _, err := io.WriteString(opts.Output, res.Output); err != nil {
+					return append(results, res), err
+				}
+			}
+		}
+		results = append(results, res)
+		if res.Err != nil && exitOnError {
+			return results, &ScriptError{Err: res.Err, ScriptStatement: st}
+		}
+	}
+	return results, nil
+}
+
+// scriptCommand returns the first word of the command, uppercased and un-abbreviated, and the rest.
+func scriptCommand(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return r == ' ' || r == '\t' })
+	if i < 0 {
+		i = len(s)
+	}
+	word := strings.ToUpper(s[:i])
+	for _, full := range [...]struct {
+		Name   string
+		MinLen int
+	}{
+		{"PROMPT", 3}, {"DEFINE", 3}, {"UNDEFINE", 5}, {"REMARK", 3},
+		{"SERVEROUTPUT", 9}, {"SQLERROR", 8}, {"WHENEVER", 8},
+		{"EXIT", 4}, {"QUIT", 4}, {"SET", 3},
+	} {
+		if len(word) >= full.MinLen && strings.HasPrefix(full.Name, word) {
+			word = full.Name
+			break
+		}
+	}
+	if word == "QUIT" {
+		word = "EXIT"
+	}
+	return word, strings.TrimSpace(s[i:])
+}
+
+// ignoredScriptCommands are the SQL*Plus commands that are silently skipped.
+var ignoredScriptCommands = map[string]struct{}{
+	"SHOW": {}, "SHO": {}, "SPOOL": {}, "SPO": {}, "COLUMN": {}, "COL": {},
+	"CLEAR": {}, "CL": {}, "TTITLE": {}, "BTITLE": {}, "BREAK": {}, "COMPUTE": {},
+	"PAUSE": {}, "ACCEPT": {}, "ACC": {}, "HOST": {}, "TIMING": {}, "STORE": {},
+	"CONNECT": {}, "CONN": {}, "DISCONNECT": {}, "DISC": {},
+}
+
+// ParseScript splits the SQL*Plus-like script into statements,
+// replacing the &substitution variables (with the values of DEFINE commands and defines).
+// An undefined &&name is defined with an empty value.
+//
+// See ExecScript for the supported syntax.
+func ParseScript(script io.Reader, defines map[string]string) ([]ScriptStatement, error) {
+	vars := make(map[string]string, len(defines))
+	for k, v := range defines {
+		vars[strings.ToUpper(k)] = v
+	}
+	var stmts []ScriptStatement
+	var cur []string
+	var curLine int
+	var kind ScriptStatementKind
+	var sc scriptScanner
+	defineChar := byte('&')
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(cur, "\n"))
+		cur, sc = cur[:0], scriptScanner{}
+		if kind == ScriptSQL {
+			text = trimScriptSemicolon(text)
+		}
+		if !sqllex.HasSignificant(text) {
+			return
+		}
+		stmts = append(stmts, ScriptStatement{Text: text, Line: curLine, Kind: kind})
+	}
+
+	scanner := bufio.NewScanner(script)
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	var lineNo int
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+
+		if len(cur) == 0 {
+			if trimmed == "" || trimmed == "/" || strings.HasPrefix(trimmed, "--") {
+				continue
+			}
+			cmd, rest := scriptCommand(trimmed)
+			switch cmd {
+			case "REMARK":
+				continue
+			case "DEFINE":
+				if name, value, ok := stringsCut(rest, "="); ok {
+					vars[strings.ToUpper(strings.TrimSpace(name))] = unquoteScriptValue(value)
+				}
+				continue
+			case "UNDEFINE":
+				for _, name := range strings.Fields(rest) {
+					delete(vars, strings.ToUpper(name))
+				}
+				continue
+			case "SET":
+				name, value := scriptCommand(rest)
+				if name == "TRANSACTION" || name == "ROLE" || strings.HasPrefix(name, "CONSTRAINT") {
+					break // SQL
+				}
+				if name == "DEFINE" {
+					switch v := strings.ToUpper(strings.TrimSpace(value)); v {
+					case "ON":
+						defineChar = '&'
+					case "OFF":
+						defineChar = 0
+					default:
+						if len(v) == 1 {
+							defineChar = value[0]
+						}
+					}
+					continue
+				}
+				stmts = append(stmts, ScriptStatement{Text: trimmed, Line: lineNo, Kind: ScriptCommand})
+				continue
+			case "PROMPT", "WHENEVER", "EXIT":
+				if defineChar != 0 {
+					var err error
+					if trimmed, err = substituteScriptVars(trimmed, defineChar, vars); err != nil {
+						return stmts, fmt.Errorf("line %d: %w", lineNo, err)
+					}
+				}
+				stmts = append(stmts, ScriptStatement{Text: trimmed, Line: lineNo, Kind: ScriptCommand})
+				continue
+			}
+			if _, ok := ignoredScriptCommands[cmd]; ok || strings.HasPrefix(trimmed, "@") {
+				continue
+			}
+			curLine, kind = lineNo, ScriptSQL
+			if isPLSQLStart(trimmed) {
+				kind = ScriptPLSQL
+			}
+		} else if trimmed == "/" && !sc.inside() {
+			flush()
+			continue
+		}
+
+		if defineChar != 0 {
+			var err error
+			if line, err = substituteScriptVars(line, defineChar, vars); err != nil {
+				return stmts, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+		}
+		cur = append(cur, line)
+		endsWithSemicolon := sc.scanLine(line)
+		if kind == ScriptSQL && endsWithSemicolon {
+			flush()
+		} else if !sc.inside() && !sqllex.HasSignificant(strings.Join(cur, "\n")) {
+			// only comments so far - the next line may be a command
+			cur, sc = cur[:0], scriptScanner{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stmts, err
+	}
+	if len(cur) != 0 {
+		flush()
+	}
+	return stmts, nil
+}
+
+// isPLSQLStart reports whether the statement starting with s is a PL/SQL block,
+// which must be terminated with a "/" line.
+func isPLSQLStart(s string) bool {
+	words := make([]string, 0, 6)
+	for lx := sqllex.New(s); len(words) < cap(words); {
+		t := lx.Next()
+		if t.Kind == sqllex.EOF {
+			break
+		}
+		if t.Kind == sqllex.Ident || t.Kind == sqllex.Punct && t.Text == "<<" {
+			words = append(words, strings.ToUpper(t.Text))
+		} else if t.IsSignificant() {
+			break
+		}
+	}
+	return sqllex.IsPLSQL(words)
+}
+
+// unquoteScriptValue trims the spaces and the quotes around the DEFINE's value.
+func unquoteScriptValue(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// substituteScriptVars replaces the &name and &&name substitution variables in line.
+// A "." right after the name ends the name, and is removed.
+// &&name defines the variable with an empty value if it is not defined yet (stored in vars,
+// so the later &name references get that value), &name of an undefined variable is an error.
+func substituteScriptVars(line string, defineChar byte, vars map[string]string) (string, error) {
+	if strings.IndexByte(line, defineChar) < 0 {
+		return line, nil
+	}
+	var buf strings.Builder
+	buf.Grow(len(line))
+	for {
+		i := strings.IndexByte(line, defineChar)
+		if i < 0 {
+			buf.WriteString(line)
+			return buf.String(), nil
+		}
+		buf.WriteString(line[:i])
+		line = line[i+1:]
+		double := len(line) != 0 && line[0] == defineChar
+		if double {
+			line = line[1:]
+		}
+		j := 0
+		for j < len(line) && isIdentChar(line[j]) {
+			j++
+		}
+		if j == 0 {
+			buf.WriteByte(defineChar)
+			if double {
+				buf.WriteByte(defineChar)
+			}
+			continue
+		}
+		name := strings.ToUpper(line[:j])
+		if line = line[j:]; len(line) != 0 && line[0] == '.' {
+			line = line[1:]
+		}
+		value, ok := vars[name]
+		if !ok && double {
+			vars[name], ok = "", true
+		}
+		if !ok {
+			return "", fmt.Errorf("substitution variable %q is not defined", name)
+		}
+		buf.WriteString(value)
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '_' || c == '$' || c == '#'
+}
+
+// scriptScanner tracks the string literals and comments spanning multiple lines.
+type scriptScanner struct {
+	pending string // the unterminated string/quoted identifier/comment of the previous lines
+}
+
+func (sc scriptScanner) inside() bool { return sc.pending != "" }
+
+// scanLine scans the line, and returns whether it ends with a ";" outside strings and comments.
+func (sc *scriptScanner) scanLine(line string) bool {
+	if sc.pending != "" {
+		line = sc.pending + "\n" + line
+	}
+	sc.pending = ""
+	var last sqllex.Token
+	for lx := sqllex.New(line); ; {
+		t := lx.Next()
+		if t.Kind == sqllex.EOF {
+			break
+		}
+		if t.Unterminated {
+			sc.pending = t.Text
+			return false
+		}
+		if t.IsSignificant() {
+			last = t
+		}
+	}
+	return last.Kind == sqllex.Punct && last.Text == ";"
+}
+
+// trimScriptSemicolon cuts the terminating ";" (and the comments after it) from the SQL statement.
+func trimScriptSemicolon(text string) string {
+	var last sqllex.Token
+	for lx := sqllex.New(text); ; {
+		t := lx.Next()
+		if t.Kind == sqllex.EOF {
+			break
+		}
+		if t.IsSignificant() {
+			last = t
+		}
+	}
+	if last.Kind == sqllex.Punct && last.Text == ";" {
+		return strings.TrimSpace(text[:last.Offset])
+	}
+	return text
+}
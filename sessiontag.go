@@ -0,0 +1,68 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"fmt"
+)
+
+type sessionTagCtxKey struct{}
+
+// ContextWithSessionTag returns a context which requests a pooled session with the given tag,
+// such as "NLS_DATE_FORMAT=YYYY-MM-DD;TIME_ZONE=UTC", which should represent the session's state.
+// With matchAny, a session with a different tag (or untagged) is acceptable.
+//
+// If the acquired session has the requested tag, OnInit (and AlterSession) is skipped.
+// Otherwise CommonParams.OnSessionTag is called to set the state, and the session is retagged on release.
+// Without OnSessionTag, the tag stands for the state set by OnInit: the session is retagged after OnInit.
+//
+// The tag is used when database/sql opens or reuses a connection, so use it with *sql.Conn or *sql.Tx.
+func ContextWithSessionTag(ctx context.Context, tag string, matchAny bool) context.Context {
+	return context.WithValue(ctx, sessionTagCtxKey{}, sessionTag{Requested: tag, MatchAny: matchAny})
+}
+
+// sessionTag is the tag requested for the pooled session, and the tag of the acquired session.
+type sessionTag struct {
+	Requested, Actual string
+	MatchAny, Found   bool
+}
+
+// sessionTagFromContext returns a new sessionTag if the context requests one, else nil.
+func sessionTagFromContext(ctx context.Context) *sessionTag {
+	if ctx == nil {
+		return nil
+	}
+	if tag, ok := ctx.Value(sessionTagCtxKey{}).(sessionTag); ok && tag.Requested != "" {
+		return &tag
+	}
+	return nil
+}
+
+// found reports whether the acquired session has the requested tag, so its state needn't be set.
+func (tag *sessionTag) found() bool {
+	return tag != nil && tag.Found && tag.Actual == tag.Requested
+}
+
+// fixupSessionTag calls OnSessionTag if the session's tag does not match the requested one,
+// and marks the session for retagging on release, if it succeeds (or is nil, as OnInit has run).
+func (c *conn) fixupSessionTag(ctx context.Context, tag *sessionTag) error {
+	c.retag = ""
+	if tag == nil || tag.found() || c.poolKey == "" {
+		return nil
+	}
+	f := c.params.OnSessionTag
+	if f == nil {
+		// OnInit has set the session's state
+		c.retag = tag.Requested
+		return nil
+	}
+	if err := f(ctx, c, tag.Requested, tag.Actual); err != nil {
+		return fmt.Errorf("OnSessionTag(%q, %q): %w", tag.Requested, tag.Actual, err)
+	}
+	c.retag = tag.Requested
+	return nil
+}
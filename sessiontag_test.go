@@ -0,0 +1,41 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSessionTagFromContext(t *testing.T) {
+	t.Parallel()
+	if tag := sessionTagFromContext(context.Background()); tag != nil {
+		t.Errorf("got %+v from empty context", tag)
+	}
+	if tag := sessionTagFromContext(ContextWithSessionTag(context.Background(), "", true)); tag != nil {
+		t.Errorf("got %+v for empty tag", tag)
+	}
+	ctx := ContextWithSessionTag(context.Background(), "A=1", true)
+	tag := sessionTagFromContext(ctx)
+	if tag == nil || tag.Requested != "A=1" || !tag.MatchAny {
+		t.Fatalf("got %+v", tag)
+	}
+	if tag.found() {
+		t.Error("found before acquire")
+	}
+	// each acquire gets its own copy
+	if tag2 := sessionTagFromContext(ctx); tag2 == tag {
+		t.Error("got the same pointer")
+	}
+	tag.Found, tag.Actual = true, "B=2" // matchAny returned a differently tagged session
+	if tag.found() {
+		t.Error("found a differently tagged session")
+	}
+	tag.Actual = "A=1"
+	if !tag.found() {
+		t.Error("did not find the requested tag")
+	}
+}
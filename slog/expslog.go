@@ -40,5 +40,7 @@ func NewRecord(t time.Time, lvl slog.Level, s string, p uintptr) slog.Record {
 
 func String(k, v string) slog.Attr                             { return slog.String(k, v) }
 func StringValue(value string) slog.Value                      { return slog.StringValue(value) }
+func Int(k string, v int) slog.Attr                            { return slog.Int(k, v) }
+func Int64(k string, v int64) slog.Attr                        { return slog.Int64(k, v) }
 func NewJSONHandler(w io.Writer, opts *HandlerOptions) Handler { return slog.NewJSONHandler(w, opts) }
 func NewTextHandler(w io.Writer, opts *HandlerOptions) Handler { return slog.NewTextHandler(w, opts) }
@@ -0,0 +1,159 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godror/godror/slog"
+)
+
+// ociAttrSQLID is OCI_ATTR_SQL_ID of the statement handle (since Oracle Client 12.2).
+const ociAttrSQLID = 504
+
+// CaptureSQLID is an option to set *dest to the SQL_ID of the statement after its execution.
+// It applies only to the next execution.
+//
+// Use it "naked", without sql.Named!
+func CaptureSQLID(dest *string) Option {
+	return func(o *stmtOptions) { o.sqlID = dest }
+}
+
+// sqlID returns the SQL_ID of the executed statement.
+func (st *statement) sqlID() (string, error) {
+	if st == nil || st.dpiStmt == nil {
+		return "", nil
+	}
+	v, err := st.GetOCIAttr(ociAttrSQLID, OCIAttrText)
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// setCapturedSQLID sets the destination of CaptureSQLID, if any.
+func (st *statement) setCapturedSQLID() {
+	dest := st.stmtOptions.sqlID
+	if dest == nil {
+		return
+	}
+	st.stmtOptions.sqlID = nil
+	*dest, _ = st.sqlID()
+}
+
+// slowQuery is the database time and the number of rows of an execution, for logging it if it's slow.
+type slowQuery struct {
+	logger  *slog.Logger
+	binds   string
+	elapsed time.Duration
+	rows    int64
+}
+
+func (c *conn) slowQueryThreshold() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return c.params.SlowQueryThreshold
+}
+
+// logSlowQuery logs the execution with its SQL_ID, if its elapsed time reached the threshold.
+// With SlowQueryPlan, the child number and the plan hash value of the cursor are looked up in V$SQL, if accessible.
+func (st *statement) logSlowQuery(operation string, sq slowQuery) {
+	threshold := st.conn.slowQueryThreshold()
+	if threshold <= 0 || sq.elapsed < threshold || sq.logger == nil {
+		return
+	}
+	attrs := []interface{}{
+		"operation", operation, "stmt", st.query,
+		"elapsed", sq.elapsed, "rows", sq.rows, "binds", sq.binds,
+	}
+	sqlID, err := st.sqlID()
+	if err != nil {
+		attrs = append(attrs, "sqlIDError", err)
+	} else if sqlID != "" {
+		attrs = append(attrs, "sqlID", sqlID)
+		if st.conn.params.SlowQueryPlan {
+			if child, planHash, err := st.conn.sqlChildAndPlan(sqlID); err != nil {
+				attrs = append(attrs, "planError", err)
+			} else {
+				attrs = append(attrs, "childNumber", child, "planHashValue", planHash)
+			}
+		}
+	}
+	sq.logger.Warn("slow query", attrs...)
+}
+
+// sqlChildAndPlan returns the child number and plan hash value of the latest active cursor of the SQL_ID.
+func (c *conn) sqlChildAndPlan(sqlID string) (child, planHash int64, err error) {
+	const qry = `SELECT TO_CHAR(child_number), TO_CHAR(plan_hash_value) FROM (
+  SELECT child_number, plan_hash_value FROM v$sql WHERE sql_id = :1 ORDER BY last_active_time DESC
+) WHERE ROWNUM = 1`
+	ctx, cancel := context.WithTimeout(context.Background(), baseWaitTimeout)
+	defer cancel()
+	c.mu.RLock()
+	st, err := c.prepareContextNotLocked(ctx, qry)
+	c.mu.RUnlock()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer st.Close()
+	rows, err := st.(*statement).queryContextNotLocked(ctx, []driver.NamedValue{{Ordinal: 1, Value: sqlID}})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+	vals := make([]driver.Value, 2)
+	if err = rows.Next(vals); err != nil {
+		if err == io.EOF {
+			err = fmt.Errorf("%s not found in V$SQL", sqlID)
+		}
+		return 0, 0, err
+	}
+	for i, dest := range []*int64{&child, &planHash} {
+		if *dest, err = strconv.ParseInt(fmt.Sprint(vals[i]), 10, 64); err != nil {
+			return 0, 0, err
+		}
+	}
+	return child, planHash, nil
+}
+
+// bindSummary summarizes the bind variables: their types (and lengths of the arrays),
+// without their values.
+func bindSummary(args []driver.NamedValue) string {
+	var buf strings.Builder
+	for i, a := range args {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		if a.Name != "" {
+			buf.WriteString(a.Name + ":")
+		}
+		v := a.Value
+		if o, ok := v.(sql.Out); ok {
+			buf.WriteString("out ")
+			v = o.Dest
+		}
+		if v == nil {
+			buf.WriteString("nil")
+			continue
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			fmt.Fprintf(&buf, "%s[%d]", rv.Type(), rv.Len())
+		} else {
+			buf.WriteString(rv.Type().String())
+		}
+	}
+	return buf.String()
+}
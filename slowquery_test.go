@@ -0,0 +1,29 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestBindSummary(t *testing.T) {
+	t.Parallel()
+	var s string
+	got := bindSummary([]driver.NamedValue{
+		{Ordinal: 1, Value: int64(1)},
+		{Ordinal: 2, Name: "nm", Value: []string{"a", "b"}},
+		{Ordinal: 3, Value: []byte("secret")},
+		{Ordinal: 4, Value: nil},
+		{Ordinal: 5, Value: sql.Out{Dest: &s}},
+		{Ordinal: 6, Value: time.Time{}},
+	})
+	if want := "int64, nm:[]string[2], []uint8, nil, out *string, time.Time"; got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+}
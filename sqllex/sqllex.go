@@ -0,0 +1,432 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+// Package sqllex is a lexer for Oracle SQL and PL/SQL.
+//
+// It classifies the text into whitespace, comments, identifiers, quoted identifiers,
+// string (also N'...' and q'[...]') and number literals, bind variables and punctuation,
+// so the bind variables and statement terminators can be found safely.
+package sqllex
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a Token.
+type Kind uint8
+
+const (
+	// EOF is returned at the end of the input.
+	EOF = Kind(iota)
+	// Space is a run of whitespace.
+	Space
+	// Comment is a -- line comment (without the newline) or a /* block comment */ (hints, too).
+	Comment
+	// Ident is an unquoted identifier or keyword.
+	Ident
+	// QuotedIdent is a "quoted identifier".
+	QuotedIdent
+	// String is a string literal: 'abc', N'abc', q'[abc]', nq'{abc}'.
+	String
+	// Number is a numeric literal: 1, 1.5, .5e-3, 2f.
+	Number
+	// BindVar is a bind variable: :name, :1, :"name".
+	BindVar
+	// Punct is an operator or punctuation: ; , ( ) := => || ? ...
+	Punct
+)
+
+func (k Kind) String() string {
+	switch k {
+	case EOF:
+		return "EOF"
+	case Space:
+		return "Space"
+	case Comment:
+		return "Comment"
+	case Ident:
+		return "Ident"
+	case QuotedIdent:
+		return "QuotedIdent"
+	case String:
+		return "String"
+	case Number:
+		return "Number"
+	case BindVar:
+		return "BindVar"
+	case Punct:
+		return "Punct"
+	default:
+		return fmt.Sprintf("Kind(%d)", uint8(k))
+	}
+}
+
+// Token is a lexical unit of the source.
+type Token struct {
+	// Text is the source text of the token, verbatim.
+	Text string
+	// Offset is the byte offset of the token in the source.
+	Offset int
+	Kind   Kind
+	// Unterminated is true for a string, quoted identifier or block comment
+	// which is not closed till the end of the source.
+	Unterminated bool
+}
+
+func (t Token) String() string { return fmt.Sprintf("%s(%q)@%d", t.Kind, t.Text, t.Offset) }
+
+// BindName returns the name of the bind variable (without the colon and the quotes),
+// or the empty string if the token is not a BindVar.
+func (t Token) BindName() string {
+	if t.Kind != BindVar {
+		return ""
+	}
+	name := t.Text[1:]
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		return name[1 : len(name)-1]
+	}
+	return name
+}
+
+// IsSignificant reports whether the token is not whitespace or comment (or EOF).
+func (t Token) IsSignificant() bool {
+	return !(t.Kind == EOF || t.Kind == Space || t.Kind == Comment)
+}
+
+// Lexer splits the source into Tokens.
+type Lexer struct {
+	src string
+	pos int
+}
+
+// New returns a new Lexer for the source.
+func New(src string) *Lexer { return &Lexer{src: src} }
+
+// Tokenize returns all the tokens of src (without the closing EOF).
+func Tokenize(src string) []Token {
+	var tokens []Token
+	for lx := New(src); ; {
+		t := lx.Next()
+		if t.Kind == EOF {
+			return tokens
+		}
+		tokens = append(tokens, t)
+	}
+}
+
+// Next returns the next Token, or a Token with Kind EOF at the end of the source.
+func (lx *Lexer) Next() Token {
+	src, start := lx.src, lx.pos
+	if start >= len(src) {
+		return Token{Kind: EOF, Offset: len(src)}
+	}
+	tok := func(kind Kind, end int, unterminated bool) Token {
+		lx.pos = end
+		return Token{Kind: kind, Text: src[start:end], Offset: start, Unterminated: unterminated}
+	}
+	at := func(i int) byte {
+		if i < len(src) {
+			return src[i]
+		}
+		return 0
+	}
+
+	c := src[start]
+	switch {
+	case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+		end := start + 1
+		for end < len(src) && strings.IndexByte(" \t\n\r\f\v", src[end]) >= 0 {
+			end++
+		}
+		return tok(Space, end, false)
+
+	case c == '-' && at(start+1) == '-':
+		if i := strings.IndexByte(src[start:], '\n'); i >= 0 {
+			return tok(Comment, start+i, false)
+		}
+		return tok(Comment, len(src), false)
+
+	case c == '/' && at(start+1) == '*':
+		if i := strings.Index(src[start+2:], "*/"); i >= 0 {
+			return tok(Comment, start+2+i+2, false)
+		}
+		return tok(Comment, len(src), true)
+
+	case c == '\'':
+		end, ok := scanQuoted(src, start+1, '\'')
+		return tok(String, end, !ok)
+
+	case c == '"':
+		end, ok := scanQuoted(src, start+1, '"')
+		return tok(QuotedIdent, end, !ok)
+
+	case '0' <= c && c <= '9' || c == '.' && isDigit(at(start+1)):
+		return tok(Number, scanNumber(src, start), false)
+
+	case c == ':':
+		switch d := at(start + 1); {
+		case d == '"':
+			end, ok := scanQuoted(src, start+2, '"')
+			return tok(BindVar, end, !ok)
+		case isDigit(d):
+			end := start + 2
+			for end < len(src) && isDigit(src[end]) {
+				end++
+			}
+			return tok(BindVar, end, false)
+		case d == '=':
+			return tok(Punct, start+2, false)
+		default:
+			if r, _ := utf8.DecodeRuneInString(src[start+1:]); isIdentStart(r) {
+				return tok(BindVar, scanIdent(src, start+1), false)
+			}
+		}
+		return tok(Punct, start+1, false)
+	}
+
+	if r, size := utf8.DecodeRuneInString(src[start:]); isIdentStart(r) {
+		// N'national', q'[alternative quoting]', nq'{both}'
+		j := start
+		if c == 'n' || c == 'N' {
+			j++
+		}
+		if d := at(j); (d == 'q' || d == 'Q') && at(j+1) == '\'' && j+2 < len(src) {
+			delim, dsize := utf8.DecodeRuneInString(src[j+2:])
+			closing := string(qQuoteClose(delim)) + "'"
+			if i := strings.Index(src[j+2+dsize:], closing); i >= 0 {
+				return tok(String, j+2+dsize+i+len(closing), false)
+			}
+			return tok(String, len(src), true)
+		}
+		if j != start && at(j) == '\'' {
+			end, ok := scanQuoted(src, j+1, '\'')
+			return tok(String, end, !ok)
+		}
+		return tok(Ident, scanIdent(src, start+size), false)
+	}
+
+	if start+1 < len(src) {
+		switch src[start : start+2] {
+		case ":=", "=>", "||", "**", "..", "<=", ">=", "<>", "!=", "^=", "~=", "<<", ">>":
+			return tok(Punct, start+2, false)
+		}
+	}
+	_, size := utf8.DecodeRuneInString(src[start:])
+	return tok(Punct, start+size, false)
+}
+
+// scanQuoted returns the end of the quoted text starting at i (after the opening quote),
+// where the quote is escaped by doubling, and whether the closing quote has been found.
+func scanQuoted(src string, i int, quote byte) (int, bool) {
+	for {
+		j := strings.IndexByte(src[i:], quote)
+		if j < 0 {
+			return len(src), false
+		}
+		i += j + 1
+		if i < len(src) && src[i] == quote {
+			i++
+			continue
+		}
+		return i, true
+	}
+}
+
+// scanIdent returns the end of the identifier, which continues at i.
+//
+// An identifier consists of a letter optionally followed by more letters, numerals, dollar signs, underscores, and number signs.
+// http://docs.oracle.com/cd/B19306_01/appdev.102/b14261/fundamentals.htm#sthref309
+func scanIdent(src string, i int) int {
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		if !(isIdentStart(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '#') {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// scanNumber returns the end of the number literal starting at i.
+func scanNumber(src string, i int) int {
+	for i < len(src) && isDigit(src[i]) {
+		i++
+	}
+	// 1..2 is a range, not a number
+	if i < len(src) && src[i] == '.' && !(i+1 < len(src) && src[i+1] == '.') {
+		i++
+		for i < len(src) && isDigit(src[i]) {
+			i++
+		}
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if j < len(src) && (src[j] == '+' || src[j] == '-') {
+			j++
+		}
+		if j < len(src) && isDigit(src[j]) {
+			for i = j; i < len(src) && isDigit(src[i]); i++ {
+			}
+		}
+	}
+	if i < len(src) && strings.IndexByte("fFdD", src[i]) >= 0 &&
+		!(i+1 < len(src) && isIdentByte(src[i+1])) {
+		i++
+	}
+	return i
+}
+
+// qQuoteClose returns the closing delimiter for the q'X...X' quote's opening delimiter.
+func qQuoteClose(r rune) rune {
+	switch r {
+	case '[':
+		return ']'
+	case '{':
+		return '}'
+	case '(':
+		return ')'
+	case '<':
+		return '>'
+	}
+	return r
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+func isIdentByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '_' || c == '$' || c == '#'
+}
+func isIdentStart(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r > utf8.RuneSelf && unicode.IsLetter(r)
+}
+
+// Significant returns the tokens which are not whitespace or comments.
+func Significant(tokens []Token) []Token {
+	sig := make([]Token, 0, len(tokens))
+	for _, t := range tokens {
+		if t.IsSignificant() {
+			sig = append(sig, t)
+		}
+	}
+	return sig
+}
+
+// HasSignificant reports whether src has anything besides whitespace and comments.
+func HasSignificant(src string) bool {
+	for lx := New(src); ; {
+		switch t := lx.Next(); t.Kind {
+		case EOF:
+			return false
+		case Space, Comment:
+		default:
+			return true
+		}
+	}
+}
+
+// Split splits src into statements at the semicolons outside of strings, comments and PL/SQL blocks.
+// The returned statements are trimmed, without the terminating semicolon
+// (except for PL/SQL blocks, where the closing "END;" is needed).
+//
+// A PL/SQL block (DECLARE, BEGIN, or CREATE FUNCTION/PROCEDURE/PACKAGE/TRIGGER/TYPE)
+// lasts till the next "/" on a line by itself (or the end of src).
+func Split(src string) []string {
+	var stmts []string
+	start, plsql := -1, false
+	lineStart := true // only whitespace since the last newline
+	var firstWords []string
+
+	add := func(end int, trimSemicolon bool) {
+		if start >= 0 {
+			s := strings.TrimSpace(src[start:end])
+			if trimSemicolon {
+				s = strings.TrimSpace(strings.TrimSuffix(s, ";"))
+			}
+			if HasSignificant(s) {
+				stmts = append(stmts, s)
+			}
+		}
+		start, plsql, firstWords = -1, false, firstWords[:0]
+	}
+
+	for lx := New(src); ; {
+		t := lx.Next()
+		if t.Kind == EOF {
+			add(len(src), !plsql)
+			return stmts
+		}
+		wasLineStart := lineStart
+		if t.Kind == Space {
+			if strings.IndexByte(t.Text, '\n') >= 0 {
+				lineStart = true
+			}
+			continue
+		}
+		lineStart = false
+		if t.Kind == Comment {
+			continue
+		}
+		if t.Kind == Punct && t.Text == "/" && wasLineStart && onlySpaceTillEOL(src[t.Offset+1:]) {
+			add(t.Offset, !plsql)
+			continue
+		}
+		if start < 0 {
+			start = t.Offset
+		}
+		if len(firstWords) < 6 && (t.Kind == Ident || t.Kind == Punct && t.Text == "<<") {
+			firstWords = append(firstWords, strings.ToUpper(t.Text))
+			plsql = plsql || IsPLSQL(firstWords)
+		}
+		if !plsql && t.Kind == Punct && t.Text == ";" {
+			add(t.Offset, false)
+			continue
+		}
+	}
+}
+
+func onlySpaceTillEOL(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ' ', '\t', '\r':
+		case '\n':
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// IsPLSQL reports whether the statement starting with the given (uppercased) words
+// is a PL/SQL block or a stored program unit definition, which contains semicolons.
+func IsPLSQL(words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	switch words[0] {
+	case "DECLARE", "BEGIN", "<<":
+		return true
+	case "CREATE":
+	default:
+		return false
+	}
+	words = words[1:]
+	if len(words) >= 2 && words[0] == "OR" && words[1] == "REPLACE" {
+		words = words[2:]
+	}
+	for len(words) != 0 && (words[0] == "EDITIONABLE" || words[0] == "NONEDITIONABLE" || words[0] == "EDITIONING") {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return false
+	}
+	switch words[0] {
+	case "FUNCTION", "PROCEDURE", "PACKAGE", "TRIGGER", "TYPE", "LIBRARY", "JAVA":
+		return true
+	}
+	return false
+}
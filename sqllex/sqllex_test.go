@@ -0,0 +1,116 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package sqllex_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/godror/godror/sqllex"
+)
+
+func TestTokenize(t *testing.T) {
+	type tk struct {
+		Kind sqllex.Kind
+		Text string
+	}
+	for i, tc := range []struct {
+		In    string
+		Await []tk
+	}{
+		{"SELECT :a, :1, :\"b c\" FROM DUAL", []tk{
+			{sqllex.Ident, "SELECT"}, {sqllex.BindVar, ":a"}, {sqllex.Punct, ","},
+			{sqllex.BindVar, ":1"}, {sqllex.Punct, ","}, {sqllex.BindVar, `:"b c"`},
+			{sqllex.Ident, "FROM"}, {sqllex.Ident, "DUAL"},
+		}},
+		{"x:=:p#1 ||'it''s :no'", []tk{
+			{sqllex.Ident, "x"}, {sqllex.Punct, ":="}, {sqllex.BindVar, ":p#1"},
+			{sqllex.Punct, "||"}, {sqllex.String, "'it''s :no'"},
+		}},
+		{"q'[it's :no]' Nq'{:no}' N':no' Q'!:no!' quit", []tk{
+			{sqllex.String, "q'[it's :no]'"}, {sqllex.String, "Nq'{:no}'"},
+			{sqllex.String, "N':no'"}, {sqllex.String, "Q'!:no!'"}, {sqllex.Ident, "quit"},
+		}},
+		{"-- :no\n/*+ :no */\"A :no\"", []tk{
+			{sqllex.Comment, "-- :no"}, {sqllex.Comment, "/*+ :no */"}, {sqllex.QuotedIdent, `"A :no"`},
+		}},
+		{"1 1.5 .5e-3 2f 1..10 a$b_c#", []tk{
+			{sqllex.Number, "1"}, {sqllex.Number, "1.5"}, {sqllex.Number, ".5e-3"}, {sqllex.Number, "2f"},
+			{sqllex.Number, "1"}, {sqllex.Punct, ".."}, {sqllex.Number, "10"}, {sqllex.Ident, "a$b_c#"},
+		}},
+		{"p(a=>?, b=>?);", []tk{
+			{sqllex.Ident, "p"}, {sqllex.Punct, "("}, {sqllex.Ident, "a"}, {sqllex.Punct, "=>"},
+			{sqllex.Punct, "?"}, {sqllex.Punct, ","}, {sqllex.Ident, "b"}, {sqllex.Punct, "=>"},
+			{sqllex.Punct, "?"}, {sqllex.Punct, ")"}, {sqllex.Punct, ";"},
+		}},
+	} {
+		var got []tk
+		for _, tok := range sqllex.Tokenize(tc.In) {
+			if tok.Kind != sqllex.Space {
+				got = append(got, tk{Kind: tok.Kind, Text: tok.Text})
+			}
+		}
+		if !reflect.DeepEqual(got, tc.Await) {
+			t.Errorf("%d. got\n\t%v,\nwanted\n\t%v", i, got, tc.Await)
+		}
+	}
+
+	for _, s := range []string{"'abc", `"abc`, "/* abc", "q'[abc'", ":\"abc"} {
+		tokens := sqllex.Tokenize(s)
+		if len(tokens) != 1 || !tokens[0].Unterminated || tokens[0].Text != s {
+			t.Errorf("%q: wanted one unterminated token, got %v", s, tokens)
+		}
+	}
+
+	const src = "SELECT 'a' /* b */ FROM dual -- c\n"
+	var buf strings.Builder
+	for _, tok := range sqllex.Tokenize(src) {
+		if src[tok.Offset:tok.Offset+len(tok.Text)] != tok.Text {
+			t.Errorf("offset mismatch at %v", tok)
+		}
+		buf.WriteString(tok.Text)
+	}
+	if buf.String() != src {
+		t.Errorf("got %q, wanted %q", buf.String(), src)
+	}
+}
+
+func TestBindName(t *testing.T) {
+	for _, tc := range [][2]string{{":a", "a"}, {":1", "1"}, {`:"b c"`, "b c"}} {
+		tokens := sqllex.Tokenize(tc[0])
+		if len(tokens) != 1 || tokens[0].BindName() != tc[1] {
+			t.Errorf("%q: got %v, wanted %q", tc[0], tokens, tc[1])
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	const src = `CREATE TABLE t (a VARCHAR2(10)); -- comment;
+INSERT INTO t VALUES ('a;b');
+INSERT INTO t VALUES (q'[;]')
+/
+CREATE OR REPLACE PROCEDURE p IS
+BEGIN
+  NULL; -- x;
+  /* ; */
+END;
+/
+/* only comment; */
+BEGIN NULL; END;
+`
+	want := []string{
+		"CREATE TABLE t (a VARCHAR2(10))",
+		"INSERT INTO t VALUES ('a;b')",
+		"INSERT INTO t VALUES (q'[;]')",
+		"CREATE OR REPLACE PROCEDURE p IS\nBEGIN\n  NULL; -- x;\n  /* ; */\nEND;",
+		"BEGIN NULL; END;",
+	}
+	got := sqllex.Split(src)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n\t%q,\nwanted\n\t%q", got, want)
+	}
+}
@@ -54,18 +54,26 @@ type NullTime = sql.NullTime
 var nullTime interface{} = nil
 
 type stmtOptions struct {
-	boolString         boolString
-	fetchArraySize     int // zero means DefaultFetchArraySize
-	prefetchCount      int // zero means DefaultPrefetchCount, -1 is zero.
-	arraySize          int
-	callTimeout        time.Duration
-	execMode           C.dpiExecMode
-	plSQLArrays        bool
-	lobAsReader        bool
-	nullDateAsZeroTime bool
-	deleteFromCache    bool
-	numberAsString     bool
-	numberAsFloat64    bool
+	boolString          boolString
+	fetchArraySize      int // zero means DefaultFetchArraySize
+	inlineLobSize       int // LOBs up to this size are returned as string/[]byte with LobAsReader
+	adaptiveFetchBudget int // non-zero means AdaptiveFetch
+	prefetchCount       int // zero means DefaultPrefetchCount, -1 is zero.
+	arraySize           int
+	callTimeout         time.Duration
+	execMode            C.dpiExecMode
+	plSQLArrays         bool
+	lobAsReader         bool
+	nullDateAsZeroTime  bool
+	deleteFromCache     bool
+	numberAsString      bool
+	numberAsFloat64     bool
+	inListExpand        bool
+	inListType          string
+	implicitResults     *[]driver.Rows
+	batchErrors         *[]*OraErr
+	sqlID               *string
+	ociAttrs            []ociAttr
 }
 
 type boolString struct {
@@ -265,6 +273,24 @@ func ClobAsString() Option { return func(o *stmtOptions) { o.lobAsReader = false
 // Use it "naked", without sql.Named!
 func LobAsReader() Option { return func(o *stmtOptions) { o.lobAsReader = true } }
 
+// InlineLobs is an option to return the CLOB/BLOB columns up to maxSize (characters for CLOBs, bytes for BLOBs)
+// as string/[]byte, and the larger ones as Lob (as with LobAsReader).
+// BFILEs are not inlined, so they can still be scanned into BFile.
+//
+// The LOB data up to maxSize is prefetched with the locators (OCI_ATTR_DEFAULT_LOBPREFETCH_SIZE),
+// so the small LOBs are read without additional round-trips.
+//
+// ColumnTypeScanType still reports the type of the LOB column (string for CLOB, []byte for BLOB),
+// so scan into an interface{}, or a type that can handle both the inline values and *Lob.
+//
+// Use it "naked", without sql.Named!
+func InlineLobs(maxSize int) Option {
+	return func(o *stmtOptions) {
+		o.lobAsReader = maxSize > 0
+		o.inlineLobSize = maxSize
+	}
+}
+
 // CallTimeout sets the round-trip timeout (OCI_ATTR_CALL_TIMEOUT).
 //
 // See https://docs.oracle.com/en/database/oracle/oracle-database/18/lnoci/handle-and-descriptor-attributes.html#GUID-D8EE68EB-7E38-4068-B06E-DF5686379E5E
@@ -285,6 +311,27 @@ func NumberAsString() Option { return func(o *stmtOptions) { o.numberAsString =
 // NumberAsFloat64 is an option to return numbers as float64, not Number (which is a string).
 func NumberAsFloat64() Option { return func(o *stmtOptions) { o.numberAsFloat64 = true } }
 
+// CollectImplicitResults is an option to append the implicit result sets
+// (returned with DBMS_SQL.RETURN_RESULT) of the executed PL/SQL block to *dest.
+//
+// The result sets must be closed by the caller, and read on the same connection
+// (*sql.Conn or *sql.Tx). See ExecImplicitResults for a more convenient API.
+//
+// Use it "naked", without sql.Named!
+func CollectImplicitResults(dest *[]driver.Rows) Option {
+	return func(o *stmtOptions) { o.implicitResults = dest }
+}
+
+// CollectBatchErrors is an option to execute the array DML in batch error mode:
+// the rows that fail do not stop the execution, their errors are appended to *dest,
+// with OraErr.Offset() being the index of the failed row.
+// It applies only to the next execution.
+//
+// Use it "naked", without sql.Named!
+func CollectBatchErrors(dest *[]*OraErr) Option {
+	return func(o *stmtOptions) { o.batchErrors = dest }
+}
+
 const minChunkSize = 1 << 16
 
 var _ driver.Stmt = (*statement)(nil)
@@ -403,6 +450,49 @@ func newDoneCh() (<-chan struct{}, func()) {
 //
 // Cancelation/timeout is honored, execution is broken, but you may have to disable out-of-bound execution - see https://github.com/oracle/odpi/issues/116 for details.
 func (st *statement) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if st.dpiStmt == nil && st.query == getConnection {
+		return st.execContext(ctx, args)
+	}
+	call := InterceptedCall{Operation: "Exec", SQL: st.query, Args: args}
+	err := st.conn.intercept(ctx, &call, func(ctx context.Context) error {
+		ctx, ts := st.conn.startSpan(ctx, "Exec", st.query)
+		if err := st.setOCIAttrs(); err != nil {
+			ts.end(err)
+			return err
+		}
+		start := time.Now()
+		var err error
+		call.Result, err = st.execContext(ctx, call.Args)
+		if err == nil {
+			st.setCapturedSQLID()
+			if threshold := st.conn.slowQueryThreshold(); threshold > 0 {
+				if elapsed := time.Since(start); elapsed >= threshold {
+					sq := slowQuery{logger: st.conn.getLogger(ctx), elapsed: elapsed, binds: bindSummary(call.Args)}
+					if call.Result != nil {
+						sq.rows, _ = call.Result.RowsAffected()
+					}
+					st.logSlowQuery("Exec", sq)
+				}
+			}
+		}
+		if ts != nil {
+			var attrs []slog.Attr
+			if call.Result != nil {
+				if n, rErr := call.Result.RowsAffected(); rErr == nil {
+					attrs = append(attrs, slog.Int64(TraceAttrRowsAffected, n))
+				}
+			}
+			ts.end(err, attrs...)
+		}
+		return err
+	})
+	if err == nil && call.Result == nil {
+		err = errNoInterceptedResult
+	}
+	return call.Result, err
+}
+
+func (st *statement) execContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -423,6 +513,24 @@ func (st *statement) ExecContext(ctx context.Context, args []driver.NamedValue)
 		return driver.ResultNoRows, nil
 	}
 
+	if st.inListType != "" {
+		var closeColls func()
+		var err error
+		if args, closeColls, err = st.inListCollections(args); err != nil {
+			return nil, err
+		}
+		defer closeColls()
+	} else if st.inListExpand {
+		st2, args2, err := st.prepareInLists(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		if st2 != nil {
+			defer st2.Close()
+			return st2.ExecContext(ctx, args2)
+		}
+	}
+
 	st.conn.mu.RLock()
 	defer st.conn.mu.RUnlock()
 
@@ -459,6 +567,12 @@ func (st *statement) ExecContext(ctx context.Context, args []driver.NamedValue)
 	// execute
 	var f func() C.int
 	many := !st.PlSQLArrays() && st.arrLen > 0
+	// batch errors are collected for this execution only
+	batchErrors := st.batchErrors
+	st.batchErrors = nil
+	if many && batchErrors != nil {
+		mode |= C.DPI_MODE_EXEC_BATCH_ERRORS
+	}
 	if many {
 		f = func() C.int { return C.dpiStmt_executeMany(st.dpiStmt, mode, C.uint32_t(st.arrLen)) }
 	} else {
@@ -548,6 +662,18 @@ func (st *statement) ExecContext(ctx context.Context, args []driver.NamedValue)
 			return nil, closeIfBadConn(fmt.Errorf("%d. get: %w", i, err))
 		}
 	}
+	if st.implicitResults != nil {
+		sets, err := st.getImplicitResults(ctx)
+		*st.implicitResults = append(*st.implicitResults, sets...)
+		if err != nil {
+			return nil, closeIfBadConn(err)
+		}
+	}
+	if many && batchErrors != nil {
+		if err := st.getBatchErrors(batchErrors); err != nil {
+			return nil, closeIfBadConn(err)
+		}
+	}
 	var count C.uint64_t
 	if st.checkExec(func() C.int { return C.dpiStmt_getRowCount(st.dpiStmt, &count) }) != nil {
 		return nil, nil
@@ -555,12 +681,62 @@ func (st *statement) ExecContext(ctx context.Context, args []driver.NamedValue)
 	return driver.RowsAffected(count), nil
 }
 
+// getBatchErrors appends the batch errors of the last execution to dest.
+func (st *statement) getBatchErrors(dest *[]*OraErr) error {
+	var n C.uint32_t
+	if err := st.checkExec(func() C.int { return C.dpiStmt_getBatchErrorCount(st.dpiStmt, &n) }); err != nil {
+		return fmt.Errorf("getBatchErrorCount: %w", err)
+	}
+	if n == 0 {
+		return nil
+	}
+	infos := make([]C.dpiErrorInfo, int(n))
+	if err := st.checkExec(func() C.int { return C.dpiStmt_getBatchErrors(st.dpiStmt, n, &infos[0]) }); err != nil {
+		return fmt.Errorf("getBatchErrors: %w", err)
+	}
+	for _, info := range infos {
+		var oe *OraErr
+		if errors.As(fromErrorInfo(info), &oe) {
+			*dest = append(*dest, oe)
+		}
+	}
+	return nil
+}
+
 // QueryContext executes a query that may return rows, such as a SELECT.
 //
 // QueryContext must honor the context timeout and return when it is canceled.
 //
 // Cancelation/timeout is honored, execution is broken, but you may have to disable out-of-bound execution - see https://github.com/oracle/odpi/issues/116 for details.
 func (st *statement) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	call := InterceptedCall{Operation: "Query", SQL: st.query, Args: args}
+	err := st.conn.intercept(ctx, &call, func(ctx context.Context) error {
+		ctx, ts := st.conn.startSpan(ctx, "Query", st.query)
+		if err := st.setOCIAttrs(); err != nil {
+			ts.end(err)
+			return err
+		}
+		start := time.Now()
+		var err error
+		call.Rows, err = st.queryContext(ctx, call.Args)
+		if err == nil {
+			st.setCapturedSQLID()
+			if st.conn.slowQueryThreshold() > 0 {
+				if r, ok := call.Rows.(*rows); ok {
+					r.slowQuery = &slowQuery{logger: st.conn.getLogger(ctx), elapsed: time.Since(start), binds: bindSummary(call.Args)}
+				}
+			}
+		}
+		ts.end(err)
+		return err
+	})
+	if err == nil && call.Rows == nil {
+		err = errNoInterceptedResult
+	}
+	return call.Rows, err
+}
+
+func (st *statement) queryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -570,6 +746,29 @@ func (st *statement) QueryContext(ctx context.Context, args []driver.NamedValue)
 	if st.conn == nil {
 		return nil, driver.ErrBadConn
 	}
+	if st.inListType != "" {
+		var closeColls func()
+		var err error
+		if args, closeColls, err = st.inListCollections(args); err != nil {
+			return nil, err
+		}
+		defer closeColls()
+	} else if st.inListExpand {
+		st2, args2, err := st.prepareInLists(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		if st2 != nil {
+			dr, err := st2.QueryContext(ctx, args2)
+			if r, ok := dr.(*rows); err == nil && ok && r.statement == st2 {
+				// the rows owns st2 from now on, and closes it on Close
+				C.dpiStmt_release(st2.dpiStmt)
+			} else {
+				st2.Close()
+			}
+			return dr, err
+		}
+	}
 	st.conn.mu.RLock()
 	defer st.conn.mu.RUnlock()
 	return st.queryContextNotLocked(ctx, args)
@@ -624,6 +823,10 @@ func (st *statement) queryContextNotLocked(ctx context.Context, args []driver.Na
 	if !st.inTransaction {
 		mode |= C.DPI_MODE_EXEC_COMMIT_ON_SUCCESS
 	}
+	if st.adaptiveFetchBudget > 0 {
+		st.fetchArraySize = adaptiveFetchSizes.get(st.query)
+		st.prefetchCount = st.fetchArraySize
+	}
 	// set Prefetch Parameters before execute
 	C.dpiStmt_setFetchArraySize(st.dpiStmt, C.uint32_t(st.FetchArraySize()))
 	C.dpiStmt_setPrefetchRows(st.dpiStmt, C.uint32_t(st.PrefetchCount()))
@@ -1016,6 +1219,12 @@ func (st *statement) bindVarTypeSwitch(ctx context.Context, info *argInfo, get *
 	}
 
 	switch v := value.(type) {
+	case BFile:
+		info.typ, info.natTyp = C.DPI_ORACLE_TYPE_BFILE, C.DPI_NATIVE_TYPE_LOB
+		info.set = st.dataSetBFile
+		if info.isOut {
+			*get = st.dataGetBFile
+		}
 	case Lob, []Lob:
 		info.typ, info.natTyp = C.DPI_ORACLE_TYPE_BLOB, C.DPI_NATIVE_TYPE_LOB
 		var isClob bool
@@ -2578,7 +2787,7 @@ func (c *conn) dataGetLOBC(ctx context.Context, L *Lob, data *C.dpiData) {
 	if lob == nil {
 		return
 	}
-	L.Reader = &dpiLobReader{drv: c.drv, dpiLob: lob, IsClob: L.IsClob}
+	L.Reader = &dpiLobReader{drv: c.drv, conn: c, ctx: ctx, dpiLob: lob, IsClob: L.IsClob}
 }
 
 func (c *conn) dataSetLOB(ctx context.Context, dv *C.dpiVar, data []C.dpiData, vv interface{}) error {
@@ -3553,6 +3762,12 @@ func (st *statement) openRows(ctx context.Context, colCount int) (*rows, error)
 		columns:   make([]Column, colCount),
 		vars:      make([]*C.dpiVar, colCount),
 		data:      make([][]C.dpiData, colCount),
+		defines:   make([]varInfo, colCount),
+	}
+
+	// The LOB locators get the prefetch size of the session at define time.
+	if err := st.conn.setLobPrefetchSize(st.inlineLobSize); err != nil {
+		return nil, err
 	}
 
 	var info C.dpiQueryInfo
@@ -3621,6 +3836,7 @@ func (st *statement) openRows(ctx context.Context, colCount int) (*rows, error)
 		if r.vars[i], r.data[i], err = st.newVar(vi); err != nil {
 			return nil, err
 		}
+		r.defines[i] = vi
 
 		if err = st.checkExecNoLOT(func() C.int {
 			return C.dpiStmt_define(st.dpiStmt, C.uint32_t(i+1), r.vars[i])
@@ -3634,6 +3850,9 @@ func (st *statement) openRows(ctx context.Context, colCount int) (*rows, error)
 		return &r, fmt.Errorf("dpiStmt_addRef: %w", err)
 	}
 	st.columns = r.columns
+	if st.adaptiveFetchBudget > 0 {
+		r.rowWidth = rowWidth(r.columns)
+	}
 	return &r, nil
 }
 
@@ -0,0 +1,200 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/godror/godror/slog"
+)
+
+// Tracer starts spans for the database operations.
+//
+// It is modeled after OpenTelemetry's trace.Tracer, so an adapter is a few lines,
+// without the driver depending on OpenTelemetry.
+type Tracer interface {
+	// Start a span for the operation (such as "Exec"), with the attributes.
+	Start(ctx context.Context, operation string, attrs ...slog.Attr) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer, such as OpenTelemetry's trace.Span.
+type Span interface {
+	SetAttributes(attrs ...slog.Attr)
+	// RecordError records the error, and sets the status of the span to error.
+	RecordError(err error)
+	End()
+}
+
+// TraceIDSpan is an optional interface of Span, returning the (hex encoded) trace ID,
+// for correlating the session with the trace.
+type TraceIDSpan interface {
+	TraceID() string
+}
+
+// The attribute keys of the spans, following the OpenTelemetry semantic conventions.
+const (
+	TraceAttrSystem       = "db.system"
+	TraceAttrStatement    = "db.statement"
+	TraceAttrOperation    = "db.operation"
+	TraceAttrRowsAffected = "db.rows_affected"
+	TraceAttrRowsReturned = "db.response.returned_rows"
+	TraceAttrErrorCode    = "db.response.status_code"
+)
+
+// TracingOptions configures the tracing of database operations:
+// PrepareContext, ExecContext, QueryContext, fetches, LOB reads, acquiring a connection,
+// Commit and Rollback.
+type TracingOptions struct {
+	Tracer Tracer
+	// SanitizeSQL transforms the statement text before recording it as db.statement,
+	// for example to remove literals. If it returns the empty string, no db.statement is recorded.
+	SanitizeSQL func(string) string
+	// TraceIDAsClientIdentifier sets the session's CLIENT_IDENTIFIER to the span's trace ID
+	// (if the Span implements TraceIDSpan), so the sessions and AWR data can be correlated with the traces.
+	TraceIDAsClientIdentifier bool
+}
+
+var globalTracing atomic.Value
+
+// SetTracing sets the global tracing options. Setting a zero TracingOptions disables tracing.
+func SetTracing(opts TracingOptions) { globalTracing.Store(opts) }
+
+type tracingCtxKey struct{}
+
+// ContextWithTracing returns a context with the given tracing options, overriding the global ones.
+func ContextWithTracing(ctx context.Context, opts TracingOptions) context.Context {
+	return context.WithValue(ctx, tracingCtxKey{}, opts)
+}
+
+func getTracing(ctx context.Context) TracingOptions {
+	if ctx != nil {
+		if opts, ok := ctx.Value(tracingCtxKey{}).(TracingOptions); ok {
+			return opts
+		}
+	}
+	opts, _ := globalTracing.Load().(TracingOptions)
+	return opts
+}
+
+// traceSpan is a started span, which may be nil (no tracing).
+type traceSpan struct {
+	span Span
+}
+
+// startSpan starts a span for the operation, if tracing is enabled.
+// If the context is nil, the global tracing options are used.
+func startSpan(ctx context.Context, operation, qry string) (context.Context, *traceSpan) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opts := getTracing(ctx)
+	if opts.Tracer == nil {
+		return ctx, nil
+	}
+	attrs := make([]slog.Attr, 0, 3)
+	attrs = append(attrs, slog.String(TraceAttrSystem, "oracle"), slog.String(TraceAttrOperation, operation))
+	if qry != "" {
+		if opts.SanitizeSQL != nil {
+			qry = opts.SanitizeSQL(qry)
+		}
+		if qry != "" {
+			attrs = append(attrs, slog.String(TraceAttrStatement, qry))
+		}
+	}
+	ctx, span := opts.Tracer.Start(ctx, operation, attrs...)
+	if span == nil {
+		return ctx, nil
+	}
+	return ctx, &traceSpan{span: span}
+}
+
+// startSpan starts a span for the operation on the connection, setting the CLIENT_IDENTIFIER if required.
+func (c *conn) startSpan(ctx context.Context, operation, qry string) (context.Context, *traceSpan) {
+	ctx, ts := startSpan(ctx, operation, qry)
+	if ts == nil || c == nil || c.dpiConn == nil || !getTracing(ctx).TraceIDAsClientIdentifier {
+		return ctx, ts
+	}
+	if tid, ok := ts.span.(TraceIDSpan); ok {
+		if id := tid.TraceID(); id != "" {
+			tt, ok := c.traceTagFromContext(ctx)
+			if !ok {
+				tt, _ = c.currentTT.Load().(TraceTag)
+			}
+			tt.ClientIdentifier = id
+			_ = c.setTraceTag(tt)
+		}
+	}
+	return ctx, ts
+}
+
+// end the span, recording the error (and its ORA code) and the attributes.
+func (ts *traceSpan) end(err error, attrs ...slog.Attr) {
+	if ts == nil {
+		return
+	}
+	if err != nil {
+		if oe, ok := AsOraErr(err); ok {
+			attrs = append(attrs, slog.Int(TraceAttrErrorCode, oe.Code()))
+		}
+		ts.span.RecordError(err)
+	}
+	if len(attrs) != 0 {
+		ts.span.SetAttributes(attrs...)
+	}
+	ts.span.End()
+}
+
+type traceparentCtxKey struct{}
+
+// traceparent is a parsed W3C Trace Context traceparent header.
+type traceparent struct {
+	TraceID, ParentID string
+}
+
+// ContextWithTraceparent returns a context with the W3C Trace Context traceparent
+// (such as the "traceparent" header of an incoming HTTP request).
+//
+// Its trace-id is set as the ECID (execution context id) of the session used with the context,
+// unless the TraceTag of the context has an ECID.
+func ContextWithTraceparent(ctx context.Context, header string) (context.Context, error) {
+	tp, err := parseTraceparent(header)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, traceparentCtxKey{}, tp), nil
+}
+
+// parseTraceparent parses the "version-traceid-parentid-flags" header.
+func parseTraceparent(header string) (traceparent, error) {
+	var tp traceparent
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 ||
+		parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return tp, fmt.Errorf("traceparent %q: bad format", header)
+	}
+	for _, p := range parts[:4] {
+		if !isLowerHex(p) {
+			return tp, fmt.Errorf("traceparent %q: %q is not lowercase hex", header, p)
+		}
+	}
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return tp, fmt.Errorf("traceparent %q: zero trace-id or parent-id", header)
+	}
+	tp.TraceID, tp.ParentID = parts[1], parts[2]
+	return tp, nil
+}
+
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		In      string
+		TraceID string
+		WantErr bool
+	}{
+		{In: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", TraceID: "0af7651916cd43dd8448eb211c80319c"},
+		{In: "01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00-future", TraceID: "0af7651916cd43dd8448eb211c80319c"},
+		{In: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra", WantErr: true},
+		{In: "ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", WantErr: true},
+		{In: "00-00000000000000000000000000000000-b7ad6b7169203331-01", WantErr: true},
+		{In: "00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01", WantErr: true},
+		{In: "00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01", WantErr: true},
+		{In: "00-0af7651916cd43dd-b7ad6b7169203331-01", WantErr: true},
+		{In: "", WantErr: true},
+	} {
+		tp, err := parseTraceparent(tc.In)
+		if tc.WantErr {
+			if err == nil {
+				t.Errorf("%q: wanted error, got %+v", tc.In, tp)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %+v", tc.In, err)
+		} else if tp.TraceID != tc.TraceID {
+			t.Errorf("%q: got %q, wanted %q", tc.In, tp.TraceID, tc.TraceID)
+		}
+	}
+}
+
+func TestTraceTagFromContext(t *testing.T) {
+	t.Parallel()
+	var c conn
+	c.currentTT.Store(TraceTag{Module: "current", ECID: "previous"})
+	if _, ok := c.traceTagFromContext(context.Background()); ok {
+		t.Error("got TraceTag from empty context")
+	}
+	ctx, err := ContextWithTraceparent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tt, ok := c.traceTagFromContext(ctx); !ok || tt.ECID != "0af7651916cd43dd8448eb211c80319c" || tt.Module != "current" {
+		t.Errorf("got %+v (%t), wanted the trace-id as ECID, with the current Module", tt, ok)
+	}
+	ctx = ContextWithTraceTag(ctx, TraceTag{Module: "test", ECID: "explicit"})
+	if tt, ok := c.traceTagFromContext(ctx); !ok || tt.ECID != "explicit" || tt.Module != "test" {
+		t.Errorf("got %+v (%t), wanted the explicit ECID", tt, ok)
+	}
+}
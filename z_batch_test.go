@@ -8,7 +8,9 @@ package godror_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -69,3 +71,199 @@ func TestBatch(t *testing.T) {
 		t.Errorf("wanted %d rows, got %d", 3, i)
 	}
 }
+
+func TestAppendValuesLoader(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("AppendValuesLoader"), time.Minute)
+	defer cancel()
+
+	const tbl = "test_dpl"
+	_, _ = testDb.ExecContext(ctx, "DROP TABLE "+tbl)
+	if _, err := testDb.ExecContext(ctx, "CREATE TABLE "+tbl+" (F_id NUMBER(9) PRIMARY KEY, F_text VARCHAR2(10), F_date DATE)"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _, _ = testDb.ExecContext(context.Background(), "DROP TABLE "+tbl) }()
+
+	cx, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cx.Close()
+	var loaded int64
+	var rejected []godror.RejectedRow
+	if err = godror.Raw(ctx, cx, func(c godror.Conn) error {
+		l, err := c.(godror.AppendValuesConn).NewAppendValuesLoader(ctx, tbl, []string{"F_id", "F_text", "F_date"}, godror.AppendValuesOptions{BatchSize: 10, MaxRejects: -1})
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for i := 0; i < 25; i++ {
+			text := sql.NullString{String: fmt.Sprintf("text-%d", i), Valid: i%3 != 0}
+			if i == 12 {
+				text.String = "too long text" // rejected
+			}
+			if err = l.AddRow(ctx, i, text, now); err != nil {
+				l.Close(ctx)
+				return err
+			}
+		}
+		// duplicate ids: rejected
+		if err = l.AddColumns(ctx, []int{100, 101, 0}, []string{"a", "b", "c"}, []time.Time{now, now, now}); err != nil {
+			l.Close(ctx)
+			return err
+		}
+		err = l.Close(ctx)
+		loaded, rejected = l.Loaded(), l.Rejected()
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("loaded=%d rejected=%+v", loaded, rejected)
+	if loaded != 26 {
+		t.Errorf("loaded %d rows, wanted 26", loaded)
+	}
+	if len(rejected) != 2 || rejected[0].Row != 12 || rejected[1].Row != 27 {
+		t.Errorf("got rejected %+v, wanted rows 12 and 27", rejected)
+	}
+	var n int64
+	if err = testDb.QueryRowContext(ctx, "SELECT COUNT(0) FROM "+tbl).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != loaded {
+		t.Errorf("got %d rows in the table, wanted %d", n, loaded)
+	}
+}
+
+func TestBatchErrors(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("BatchErrors"), time.Minute)
+	defer cancel()
+
+	const tbl = "test_batch_errors"
+	_, _ = testDb.ExecContext(ctx, "DROP TABLE "+tbl)
+	if _, err := testDb.ExecContext(ctx, "CREATE TABLE "+tbl+" (F_id NUMBER(9) PRIMARY KEY, F_num NUMBER, F_text VARCHAR2(10))"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _, _ = testDb.ExecContext(context.Background(), "DROP TABLE "+tbl) }()
+
+	stmt, err := testDb.PrepareContext(ctx, "INSERT INTO "+tbl+" (F_id, F_num, F_text) VALUES (:1, :2, :3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	b := godror.Batch{Stmt: stmt, Limit: 100, BatchErrors: true, FlushInterval: 100 * time.Millisecond}
+	if err = b.Add(ctx, 1, sql.NullFloat64{Float64: 1.5, Valid: true}, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Add(ctx, 2, 2.5, "b"); err == nil {
+		t.Error("wanted type mismatch error")
+	}
+	// Concurrent producers, with NULLs
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				id := 10 + 10*g + i
+				var num *float64
+				if i%2 == 0 {
+					f := float64(id)
+					num = &f
+				}
+				if err := b.Add(ctx, id, num, (*string)(nil)); err != nil {
+					t.Error(err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	// duplicate and too long: rejected
+	if err = b.Add(ctx, 1, (*float64)(nil), "dup"); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Add(ctx, 99, (*float64)(nil), "too long text"); err != nil {
+		t.Fatal(err)
+	}
+	err = b.Flush(ctx)
+	var be *godror.BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("wanted BatchError, got %+v", err)
+	}
+	t.Log(be)
+	if len(be.Rows) != 2 {
+		t.Errorf("got %d failed rows, wanted 2: %+v", len(be.Rows), be.Rows)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if err = b.Add(ctx, 100, sql.NullFloat64{Float64: 100, Valid: true}, "interval"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if n := b.Size(); n != 0 {
+		t.Errorf("%d rows remained after the FlushInterval", n)
+	}
+
+	var n, nulls int
+	if err = testDb.QueryRowContext(ctx, "SELECT COUNT(0), COUNT(0) - COUNT(F_num) FROM "+tbl).Scan(&n, &nulls); err != nil {
+		t.Fatal(err)
+	}
+	if n != 42 || nulls != 20 {
+		t.Errorf("got %d rows (%d NULLs), wanted 42 (20 NULLs)", n, nulls)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("Merge"), time.Minute)
+	defer cancel()
+
+	const tbl = "test_merge"
+	_, _ = testDb.ExecContext(ctx, "DROP TABLE "+tbl)
+	if _, err := testDb.ExecContext(ctx, "CREATE TABLE "+tbl+" (F_id NUMBER(9), F_code CHAR(3), F_text VARCHAR2(20), F_date DATE, CONSTRAINT "+tbl+"_pk PRIMARY KEY (F_id, F_code))"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _, _ = testDb.ExecContext(context.Background(), "DROP TABLE "+tbl) }()
+
+	type row struct {
+		ID     int64     `godror:"F_id"`
+		Code   string    `godror:"F_code"`
+		Text   *string   `godror:"F_text"`
+		Date   time.Time `godror:"F_date"`
+		Ignore string    `godror:"-"`
+	}
+	text := "first"
+	now := time.Now().Truncate(time.Second)
+	rows := []row{{ID: 1, Code: "A", Text: &text, Date: now}, {ID: 2, Code: "B", Date: now}}
+	res, err := godror.Merge(ctx, testDb, tbl, []string{"F_id", "F_code"}, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Inserted != 2 || res.Updated != 0 {
+		t.Errorf("got %+v, wanted 2 inserted", res)
+	}
+
+	second := "second"
+	rows[1].Text = &second
+	rows = append(rows, row{ID: 3, Code: "C", Date: now})
+	if res, err = godror.Merge(ctx, testDb, tbl, []string{"f_id", "f_code"}, rows); err != nil {
+		t.Fatal(err)
+	}
+	if res.Inserted != 1 || res.Updated != 2 {
+		t.Errorf("got %+v, wanted 1 inserted, 2 updated", res)
+	}
+
+	var got string
+	if err = testDb.QueryRowContext(ctx, "SELECT F_text FROM "+tbl+" WHERE F_id = 2 AND F_code = 'B'").Scan(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got != second {
+		t.Errorf("got %q, wanted %q", got, second)
+	}
+
+	third := "third"
+	dups := []row{{ID: 4, Code: "D", Date: now}, {ID: 4, Code: "D", Text: &third, Date: now}}
+	if res, err = godror.Merge(ctx, testDb, tbl, []string{"F_id", "F_code"}, dups); err == nil {
+		t.Errorf("wanted error for duplicate keys, got %+v", res)
+	}
+}
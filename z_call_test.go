@@ -0,0 +1,113 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	godror "github.com/godror/godror"
+)
+
+func TestCall(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("Call"), time.Minute)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	pkg := "test_call" + tblSuffix
+	qry := `CREATE OR REPLACE PACKAGE ` + pkg + ` AS
+  PROCEDURE proc(p_id IN NUMBER, p_txt IN OUT VARCHAR2, p_dt OUT DATE, p_mult IN PLS_INTEGER := 2);
+  FUNCTION fun(p_n IN PLS_INTEGER) RETURN SYS_REFCURSOR;
+END;`
+	if _, err = conn.ExecContext(ctx, qry); err != nil {
+		t.Fatalf("%s: %+v", qry, err)
+	}
+	defer func() { _, _ = testDb.ExecContext(context.Background(), "DROP PACKAGE "+pkg) }()
+	qry = `CREATE OR REPLACE PACKAGE BODY ` + pkg + ` AS
+  PROCEDURE proc(p_id IN NUMBER, p_txt IN OUT VARCHAR2, p_dt OUT DATE, p_mult IN PLS_INTEGER := 2) IS
+  BEGIN
+    p_txt := p_txt||':'||(p_id * p_mult);
+    p_dt := TO_DATE('2024-01-02', 'YYYY-MM-DD');
+  END;
+  FUNCTION fun(p_n IN PLS_INTEGER) RETURN SYS_REFCURSOR IS
+    v_cur SYS_REFCURSOR;
+  BEGIN
+    OPEN v_cur FOR SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= p_n;
+    RETURN v_cur;
+  END;
+END;`
+	if _, err = conn.ExecContext(ctx, qry); err != nil {
+		t.Fatalf("%s: %+v", qry, err)
+	}
+
+	out, err := godror.Call(ctx, conn, pkg+".proc", map[string]interface{}{"p_id": 3, "p_txt": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(out)
+	if got, want := out["P_TXT"], "a:6"; got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+	if dt, ok := out["P_DT"].(time.Time); !ok || dt.Year() != 2024 {
+		t.Errorf("got %v, wanted 2024-01-02", out["P_DT"])
+	}
+
+	var args struct {
+		ID   int    `godror:"p_id"`
+		Txt  string `godror:"p_txt"`
+		Mult int    `godror:"p_mult"`
+		Date time.Time
+	}
+	args.ID, args.Txt, args.Mult = 3, "b", 3
+	if _, err = godror.Call(ctx, conn, pkg+".proc", &args); err == nil {
+		t.Error("wanted error for the unknown Date field")
+	}
+
+	var args2 struct {
+		Dt   time.Time `godror:"p_dt"`
+		Txt  string    `godror:"p_txt"`
+		ID   int       `godror:"p_id"`
+		Mult int       `godror:"p_mult"`
+	}
+	args2.ID, args2.Txt, args2.Mult = 3, "b", 3
+	if _, err = godror.Call(ctx, conn, pkg+".proc", &args2); err != nil {
+		t.Fatal(err)
+	}
+	if args2.Txt != "b:9" || args2.Dt.Year() != 2024 {
+		t.Errorf("got %+v", args2)
+	}
+
+	if out, err = godror.Call(ctx, conn, pkg+".fun", map[string]interface{}{"p_n": 3}); err != nil {
+		t.Fatal(err)
+	}
+	rows, ok := out[godror.CallReturn].(driver.Rows)
+	if !ok {
+		t.Fatalf("got %T, wanted driver.Rows", out[godror.CallReturn])
+	}
+	defer rows.Close()
+	dest := make([]driver.Value, 1)
+	var n int
+	for {
+		if err = rows.Next(dest); err != nil {
+			if err != io.EOF {
+				t.Error(err)
+			}
+			break
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("got %d rows, wanted 3", n)
+	}
+}
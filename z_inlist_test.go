@@ -0,0 +1,48 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	godror "github.com/godror/godror"
+)
+
+func TestInList(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("InList"), time.Minute)
+	defer cancel()
+
+	const qry = "SELECT COUNT(0) FROM (SELECT LEVEL AS id FROM DUAL CONNECT BY LEVEL <= 10) WHERE "
+	for name, tC := range map[string]struct {
+		Qry  string
+		Opt  godror.Option
+		IDs  []int
+		Want int
+	}{
+		"expand":      {Qry: qry + "id IN (:ids)", Opt: godror.ExpandInLists(), IDs: []int{1, 3, 5}, Want: 3},
+		"expandEmpty": {Qry: qry + "id IN (:ids)", Opt: godror.ExpandInLists(), IDs: []int{}, Want: 0},
+		"expandNotIn": {Qry: qry + "id NOT IN (:ids)", Opt: godror.ExpandInLists(), IDs: []int{1, 2, 3}, Want: 7},
+		"collection": {
+			Qry: qry + "id IN (SELECT COLUMN_VALUE FROM TABLE(:ids))",
+			Opt: godror.InListAsCollection("SYS.ODCINUMBERLIST"), IDs: []int{2, 4, 6, 8, 10, 12}, Want: 5,
+		},
+	} {
+		tC := tC
+		t.Run(name, func(t *testing.T) {
+			var n int
+			if err := testDb.QueryRowContext(ctx, tC.Qry, tC.Opt, sql.Named("ids", tC.IDs)).Scan(&n); err != nil {
+				t.Fatalf("%s: %+v", tC.Qry, err)
+			}
+			if n != tC.Want {
+				t.Errorf("got %d, wanted %d", n, tC.Want)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -492,3 +493,269 @@ type event struct {
 	Text       string
 	ID         int64
 }
+
+func TestBFile(t *testing.T) {
+	ctx, cancel := context.WithTimeout(testContext("BFile"), 30*time.Second)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	const dir, name = "DATA_PUMP_DIR", "godror-nonexistent.pdf"
+	var bf godror.BFile
+	const qry = "SELECT :1 FROM DUAL"
+	if err = conn.QueryRowContext(ctx, qry, godror.BFile{Dir: dir, Name: name}).Scan(&bf); err != nil {
+		t.Fatalf("%s: %+v", qry, err)
+	}
+	defer bf.Close()
+	if bf.Dir != dir || bf.Name != name {
+		t.Errorf("got %q/%q, wanted %q/%q", bf.Dir, bf.Name, dir, name)
+	}
+	if exists, err := bf.Exists(); err != nil {
+		t.Skip(err)
+	} else if exists {
+		t.Errorf("%q exists", name)
+	}
+
+	var out godror.BFile
+	if _, err = conn.ExecContext(ctx, "BEGIN :1 := BFILENAME(:2, :3); END;",
+		sql.Out{Dest: &out}, dir, name,
+	); err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	if out.Dir != dir || out.Name != name {
+		t.Errorf("got %q/%q, wanted %q/%q", out.Dir, out.Name, dir, name)
+	}
+
+	var inlined godror.BFile
+	const bfQry = "SELECT BFILENAME(:1, :2) FROM DUAL"
+	if err = conn.QueryRowContext(ctx, bfQry, dir, name, godror.InlineLobs(1000)).Scan(&inlined); err != nil {
+		t.Fatalf("%s with InlineLobs: %+v", bfQry, err)
+	}
+	defer inlined.Close()
+	if inlined.Dir != dir || inlined.Name != name {
+		t.Errorf("InlineLobs: got %q/%q, wanted %q/%q", inlined.Dir, inlined.Name, dir, name)
+	}
+
+	if err = conn.Raw(func(driverConn interface{}) error {
+		bf, err := driverConn.(godror.BFileConn).NewBFile(dir, name)
+		if err != nil {
+			return err
+		}
+		defer bf.Close()
+		if exists, err := bf.Exists(); err != nil {
+			return err
+		} else if exists {
+			t.Errorf("%q exists", name)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDirectLobServerOps(t *testing.T) {
+	ctx, cancel := context.WithTimeout(testContext("DirectLobServerOps"), 30*time.Second)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err = conn.Raw(func(driverConn interface{}) error {
+		dc := driverConn.(godror.Conn)
+		a, err := dc.NewTempLob(false)
+		if err != nil {
+			return err
+		}
+		defer a.Close()
+		if err = a.Set([]byte("abcdef")); err != nil {
+			return err
+		}
+		b, err := a.Copy()
+		if err != nil {
+			return err
+		}
+		defer b.Close()
+		if n, err := a.Compare(ctx, b, 0, 0, 0); err != nil {
+			return err
+		} else if n != 0 {
+			t.Errorf("copy differs: %d", n)
+		}
+
+		if err = a.Append(ctx, b); err != nil {
+			return err
+		}
+		if err = a.CopyFrom(ctx, b, 3, 12, 3); err != nil {
+			return err
+		}
+		if n, err := a.Compare(ctx, b, 3, 12, 3); err != nil {
+			return err
+		} else if n != 0 {
+			t.Errorf("range copy differs: %d", n)
+		}
+		if got, err := a.Substr(ctx, 0, 15); err != nil {
+			return err
+		} else if want := "abcdefabcdefdef"; string(got) != want {
+			t.Errorf("got %q, wanted %q", got, want)
+		}
+
+		if i, err := a.Index(ctx, []byte("cd"), 0, 2); err != nil {
+			return err
+		} else if i != 8 {
+			t.Errorf("2nd cd at %d, wanted 8", i)
+		}
+		if i, err := a.Index(ctx, []byte("xyz"), 0, 1); err != nil {
+			return err
+		} else if i != -1 {
+			t.Errorf("xyz at %d, wanted -1", i)
+		}
+
+		if n, err := a.Erase(ctx, 0, 2); err != nil {
+			return err
+		} else if n != 2 {
+			t.Errorf("erased %d, wanted 2", n)
+		}
+		if got, err := a.Substr(ctx, 0, 3); err != nil {
+			return err
+		} else if want := "\x00\x00c"; string(got) != want {
+			t.Errorf("got %q, wanted %q", got, want)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStreamLOB(t *testing.T) {
+	ctx, cancel := context.WithTimeout(testContext("StreamLOB"), 60*time.Second)
+	defer cancel()
+
+	tbl := "test_stream_lob" + tblSuffix
+	drQry := "DROP TABLE " + tbl
+	_, _ = testDb.ExecContext(ctx, drQry)
+	crQry := "CREATE TABLE " + tbl + " (F_id NUMBER(3) NOT NULL, F_blob BLOB, F_clob CLOB)"
+	if _, err := testDb.ExecContext(ctx, crQry); err != nil {
+		t.Fatal(crQry, err)
+	}
+	defer func() { _, _ = testDb.ExecContext(context.Background(), drQry) }()
+
+	tx, err := testDb.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	const size = 3<<20 + 17
+	var progress []int64
+	insQry := "INSERT INTO " + tbl + " (F_id, F_blob) VALUES (:1, EMPTY_BLOB()) RETURNING F_blob INTO :2"
+	n, err := godror.StreamLOB(ctx, tx, insQry,
+		io.LimitReader(strings.NewReader(strings.Repeat("0123456789", size/10+1)), size),
+		godror.LobStreamOptions{Progress: func(written int64) { progress = append(progress, written) }},
+		1)
+	if err != nil {
+		t.Fatalf("%s: %+v", insQry, err)
+	}
+	t.Log("progress:", progress)
+	if n != size || len(progress) < 2 || progress[len(progress)-1] != size {
+		t.Errorf("got %d (progress=%v), wanted %d", n, progress, size)
+	}
+
+	// Multi-byte characters crossing the piece boundaries.
+	text := strings.Repeat("árvíztűrő tükörfúrógép 😀", 1<<16)
+	insQry = "INSERT INTO " + tbl + " (F_id, F_clob) VALUES (:1, EMPTY_CLOB()) RETURNING F_clob INTO :2"
+	if _, err = godror.StreamLOB(ctx, tx, insQry, strings.NewReader(text),
+		godror.LobStreamOptions{IsClob: true, ChunkSize: 1000}, 2,
+	); err != nil {
+		t.Fatalf("%s: %+v", insQry, err)
+	}
+
+	var gotB, gotC int64
+	var gotText string
+	selQry := "SELECT (SELECT DBMS_LOB.getlength(F_blob) FROM " + tbl + " WHERE F_id = 1), " +
+		"F_clob FROM " + tbl + " WHERE F_id = 2"
+	if err = tx.QueryRowContext(ctx, selQry, godror.ClobAsString()).Scan(&gotB, &gotText); err != nil {
+		t.Fatalf("%s: %+v", selQry, err)
+	}
+	gotC = int64(len(gotText))
+	if gotB != size {
+		t.Errorf("BLOB: got %d, wanted %d", gotB, size)
+	}
+	if gotText != text {
+		t.Errorf("CLOB: got %d bytes, wanted %d", gotC, len(text))
+	}
+
+	if _, err = godror.StreamLOB(ctx, nil, insQry, strings.NewReader("x"), godror.LobStreamOptions{}, 3); err == nil {
+		t.Error("wanted error for nil transaction")
+	}
+	doneTx, err := testDb.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = doneTx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = godror.StreamLOB(ctx, doneTx, insQry, strings.NewReader("x"), godror.LobStreamOptions{}, 3); err == nil {
+		t.Error("wanted error for finished transaction")
+	}
+
+	cctx, ccancel := context.WithCancel(ctx)
+	ccancel()
+	insQry = "INSERT INTO " + tbl + " (F_id, F_blob) VALUES (:1, EMPTY_BLOB()) RETURNING F_blob INTO :2"
+	if _, err = godror.StreamLOB(cctx, tx, insQry, strings.NewReader("x"), godror.LobStreamOptions{}, 3); err == nil {
+		t.Error("wanted error for canceled context")
+	}
+}
+
+func TestInlineLobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(testContext("InlineLobs"), 30*time.Second)
+	defer cancel()
+
+	const qry = `SELECT TO_CLOB('small'), TO_BLOB(HEXTORAW('0102')),
+  TO_CLOB(RPAD('x', 4000, 'x'))||TO_CLOB(RPAD('y', 4000, 'y'))
+  FROM DUAL`
+	rows, err := testDb.QueryContext(ctx, qry, godror.InlineLobs(1000))
+	if err != nil {
+		t.Fatalf("%s: %+v", qry, err)
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []interface{}{"", []byte(nil), ""} {
+		if got := types[i].ScanType(); got != reflect.TypeOf(want) {
+			t.Errorf("%d. got scan type %v, wanted %T", i, got, want)
+		}
+	}
+	for rows.Next() {
+		var small, bin, large interface{}
+		if err = rows.Scan(&small, &bin, &large); err != nil {
+			t.Fatal(err)
+		}
+		if s, ok := small.(string); !ok || s != "small" {
+			t.Errorf("small: got %T(%#v)", small, small)
+		}
+		if b, ok := bin.([]byte); !ok || !bytes.Equal(b, []byte{1, 2}) {
+			t.Errorf("bin: got %T(%#v)", bin, bin)
+		}
+		L, ok := large.(*godror.Lob)
+		if !ok {
+			t.Fatalf("large: got %T, wanted *Lob", large)
+		}
+		var buf strings.Builder
+		if _, err = io.Copy(&buf, L); err != nil {
+			t.Fatal(err)
+		}
+		if buf.Len() != 8000 {
+			t.Errorf("large: got %d bytes, wanted 8000", buf.Len())
+		}
+	}
+	if err = rows.Err(); err != nil {
+		t.Error(err)
+	}
+}
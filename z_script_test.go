@@ -0,0 +1,139 @@
+// Copyright 2024 The Godror Authors
+//
+//
+// SPDX-License-Identifier: UPL-1.0 OR Apache-2.0
+
+package godror_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	godror "github.com/godror/godror"
+)
+
+func TestParseScript(t *testing.T) {
+	const script = `REM test script
+SET ECHO ON
+DEFINE tbl = 'test_script'
+-- comment
+PROMPT creating &tbl
+CREATE TABLE &tbl. (id NUMBER, txt VARCHAR2(100));
+INSERT INTO &&tbl (id, txt) VALUES (1, 'a;
+b;'); -- trailing comment
+INSERT INTO &tbl (id, txt) VALUES (2, q'[it's; ]'
+);
+CREATE OR REPLACE PROCEDURE p IS
+BEGIN
+  NULL; -- x;
+END;
+/
+BEGIN
+  DBMS_OUTPUT.PUT_LINE('/');
+END;
+/
+SELECT 1 FROM DUAL
+/
+/* only a comment */
+SET SERVEROUTPUT ON
+EXIT
+`
+	want := []godror.ScriptStatement{
+		{Line: 2, Kind: godror.ScriptCommand, Text: "SET ECHO ON"},
+		{Line: 5, Kind: godror.ScriptCommand, Text: "PROMPT creating test_script"},
+		{Line: 6, Kind: godror.ScriptSQL, Text: "CREATE TABLE test_script (id NUMBER, txt VARCHAR2(100))"},
+		{Line: 7, Kind: godror.ScriptSQL, Text: "INSERT INTO test_script (id, txt) VALUES (1, 'a;\nb;')"},
+		{Line: 9, Kind: godror.ScriptSQL, Text: "INSERT INTO test_script (id, txt) VALUES (2, q'[it's; ]'\n)"},
+		{Line: 11, Kind: godror.ScriptPLSQL, Text: "CREATE OR REPLACE PROCEDURE p IS\nBEGIN\n  NULL; -- x;\nEND;"},
+		{Line: 16, Kind: godror.ScriptPLSQL, Text: "BEGIN\n  DBMS_OUTPUT.PUT_LINE('/');\nEND;"},
+		{Line: 20, Kind: godror.ScriptSQL, Text: "SELECT 1 FROM DUAL"},
+		{Line: 23, Kind: godror.ScriptCommand, Text: "SET SERVEROUTPUT ON"},
+		{Line: 24, Kind: godror.ScriptCommand, Text: "EXIT"},
+	}
+	got, err := godror.ParseScript(strings.NewReader(script), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d statements (%+v), wanted %d", len(got), got, len(want))
+	}
+	for i, g := range got {
+		if g != want[i] {
+			t.Errorf("%d. got %+v, wanted %+v", i, g, want[i])
+		}
+	}
+
+	if _, err = godror.ParseScript(strings.NewReader("SELECT &undefined FROM DUAL;"), nil); err == nil {
+		t.Error("wanted error for undefined substitution variable")
+	}
+	if got, err = godror.ParseScript(
+		strings.NewReader("SELECT '&x' FROM DUAL;"),
+		map[string]string{"X": "y"},
+	); err != nil {
+		t.Error(err)
+	} else if len(got) != 1 || got[0].Text != "SELECT 'y' FROM DUAL" {
+		t.Errorf("got %+v", got)
+	}
+	if got, err = godror.ParseScript(
+		strings.NewReader("SELECT 'a&&empty.b' FROM DUAL;\nSELECT 'c&empty.d' FROM DUAL;"),
+		nil,
+	); err != nil {
+		t.Error(err)
+	} else if len(got) != 2 || got[0].Text != "SELECT 'ab' FROM DUAL" || got[1].Text != "SELECT 'cd' FROM DUAL" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestExecScript(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("ExecScript"), time.Minute)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tbl := "test_script" + tblSuffix
+	defer func() { _, _ = testDb.ExecContext(context.Background(), "DROP TABLE "+tbl) }()
+	const script = `SET SERVEROUTPUT ON
+PROMPT start
+CREATE TABLE &tbl (id NUMBER);
+INSERT INTO &tbl (id) SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= 3;
+SELECT * FROM no_such_table_at_all;
+BEGIN
+  DBMS_OUTPUT.PUT_LINE('inserted');
+END;
+/
+WHENEVER SQLERROR EXIT
+SELECT * FROM no_such_table_at_all;
+PROMPT unreachable
+`
+	var buf strings.Builder
+	results, err := godror.ExecScript(ctx, conn, strings.NewReader(script),
+		godror.ScriptOptions{Defines: map[string]string{"tbl": tbl}, Output: &buf})
+	for _, r := range results {
+		t.Logf("%d. %s: %v", r.Line, r.Text, r.Err)
+	}
+	var se *godror.ScriptError
+	if !errors.As(err, &se) {
+		t.Fatalf("wanted ScriptError, got %+v", err)
+	} else if se.Line != 11 {
+		t.Errorf("wanted error on line 11, got %+v", se)
+	}
+	if len(results) != 5 {
+		t.Fatalf("wanted 5 results, got %d", len(results))
+	}
+	if results[1].RowsAffected != 3 {
+		t.Errorf("wanted 3 rows inserted, got %d", results[1].RowsAffected)
+	}
+	if results[2].Err == nil {
+		t.Error("wanted error for the missing table")
+	}
+	if got, want := buf.String(), "start\ninserted\n"; got != want {
+		t.Errorf("got output %q, wanted %q", got, want)
+	}
+}
@@ -2530,6 +2530,63 @@ func TestImplicitResults(t *testing.T) {
 	}
 }
 
+func TestExecImplicitResults(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("ExecImplicitResults"), 10*time.Second)
+	defer cancel()
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	const qry = `declare
+  c1 sys_refcursor;
+  c2 sys_refcursor;
+begin
+  :1 := 'out';
+  open c1 for select 1 AS num from DUAL UNION ALL select 2 from DUAL;
+  dbms_sql.return_result(c1);
+  open c2 for select 'A' AS chr, 'B' AS chr2 from DUAL;
+  dbms_sql.return_result(c2);
+end;`
+	var out string
+	rs, err := godror.ExecImplicitResults(ctx, conn, qry, sql.Out{Dest: &out})
+	if err != nil {
+		if strings.Contains(err.Error(), "PLS-00302:") {
+			t.Skip()
+		}
+		t.Fatal(fmt.Errorf("%s: %w", qry, err))
+	}
+	defer rs.Close()
+	if out != "out" {
+		t.Errorf("got %q, wanted %q", out, "out")
+	}
+	if rs.Len() != 2 {
+		t.Fatalf("got %d result sets, wanted 2", rs.Len())
+	}
+	if cols := rs.Columns(1); !reflect.DeepEqual(cols, []string{"CHR", "CHR2"}) {
+		t.Errorf("got columns %q of the 2nd result set", cols)
+	}
+	wantRows := []int{2, 1}
+	for rs.Next() {
+		var n int
+		rows := rs.Rows()
+		for rows.Next() {
+			n++
+		}
+		if err := rows.Err(); err != nil {
+			t.Error(err)
+		}
+		t.Logf("%d. %q: %d rows", rs.Index(), rs.Columns(rs.Index()), n)
+		if n != wantRows[rs.Index()] {
+			t.Errorf("%d. got %d rows, wanted %d", rs.Index(), n, wantRows[rs.Index()])
+		}
+	}
+	if err := rs.Err(); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestStartupShutdown(t *testing.T) {
 	ensureSystemDB(t)
 	if os.Getenv("GODROR_DB_SHUTDOWN") != "1" {
@@ -3065,6 +3122,71 @@ func TestCancel(t *testing.T) {
 	}
 }
 
+func TestCancelFetch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(testContext("CancelFetch"), 30*time.Second)
+	defer cancel()
+	subCtx, subCancel := context.WithCancel(ctx)
+	defer subCancel()
+	const qry = "SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= 100000000"
+	rows, err := testDb.QueryContext(subCtx, qry, godror.FetchArraySize(128))
+	if err != nil {
+		t.Fatalf("%s: %+v", qry, err)
+	}
+	defer rows.Close()
+	var n int
+	start := time.Now()
+	for rows.Next() {
+		if n++; n == 1000 {
+			subCancel()
+		}
+	}
+	t.Logf("fetched %d rows in %s", n, time.Since(start))
+	if err = rows.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %+v, wanted %v", err, context.Canceled)
+	}
+	if n >= 1000000 {
+		t.Errorf("fetched %d rows after cancelation", n-1000)
+	}
+}
+
+func TestAdaptiveFetch(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("AdaptiveFetch"), 30*time.Second)
+	defer cancel()
+	const qry = "SELECT LEVEL, TO_CHAR(LEVEL), SYSDATE FROM DUAL CONNECT BY LEVEL <= 100000"
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+		rows, err := testDb.QueryContext(ctx, qry, godror.AdaptiveFetch(1<<20))
+		if err != nil {
+			t.Fatalf("%s: %+v", qry, err)
+		}
+		var n, sum int64
+		for rows.Next() {
+			var k int64
+			var s string
+			var d time.Time
+			if err = rows.Scan(&k, &s, &d); err != nil {
+				rows.Close()
+				t.Fatal(err)
+			}
+			if strconv.FormatInt(k, 10) != s {
+				t.Errorf("%d. got %q", k, s)
+			}
+			n++
+			sum += k
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("%d. fetched %d rows in %s", i, n, time.Since(start))
+		if n != 100000 || sum != 100000*100001/2 {
+			t.Errorf("%d. got %d rows (sum=%d)", i, n, sum)
+		}
+	}
+}
+
 func TestTimeout(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skip cancel test")
@@ -4882,6 +5004,10 @@ func TestReplaceQuestionPlaceholders(t *testing.T) {
 			"BEGIN ? := fun(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?); END;",
 			"BEGIN :1 := fun(:2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12); END;",
 		},
+		{
+			"SELECT '?', q'{?}' /* ? */, ? FROM DUAL -- ?\nWHERE a=?",
+			"SELECT '?', q'{?}' /* ? */, :1 FROM DUAL -- ?\nWHERE a=:2",
+		},
 	} {
 		if got := godror.ReplaceQuestionPlacholders(tC.Qry); got != tC.Want {
 			t.Errorf("%d. got\n%q wanted\n%q", tN, got, tC.Want)
@@ -5085,3 +5211,606 @@ func TestBindNumber(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryColumnar(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("QueryColumnar"), 30*time.Second)
+	defer cancel()
+	const qry = "SELECT LEVEL, TO_CHAR(LEVEL), CASE WHEN MOD(LEVEL, 2) = 0 THEN HEXTORAW('0A') END, SYSDATE FROM DUAL CONNECT BY LEVEL <= :1"
+	var batches, n int
+	if err := godror.QueryColumnar(ctx, testDb, qry, func(b *godror.ColumnBatch) error {
+		batches++
+		if len(b.Columns) != 4 {
+			return fmt.Errorf("got %d columns, wanted 4", len(b.Columns))
+		}
+		if k := b.Columns[1].Kind; k != godror.ColumnString {
+			t.Errorf("column 1 kind: got %d, wanted %d", k, godror.ColumnString)
+		}
+		if k := b.Columns[2].Kind; k != godror.ColumnBytes {
+			t.Errorf("column 2 kind: got %d, wanted %d", k, godror.ColumnBytes)
+		}
+		if k := b.Columns[3].Kind; k != godror.ColumnTime {
+			t.Errorf("column 3 kind: got %d, wanted %d", k, godror.ColumnTime)
+		}
+		for i := 0; i < b.Len; i++ {
+			n++
+			if got, want := fmt.Sprintf("%v", b.Columns[0].Value(i)), string(b.Columns[1].Bytes(i)); got != want {
+				t.Errorf("%d. got %q, wanted %q", n, got, want)
+			}
+			if isNull := b.Columns[2].Null[i]; isNull != (n%2 == 1) {
+				t.Errorf("%d. got null=%t", n, isNull)
+			}
+			if b.Columns[3].Time[i].IsZero() {
+				t.Errorf("%d. got zero time", n)
+			}
+		}
+		return nil
+	}, 1000, godror.FetchArraySize(100)); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("got %d rows in %d batches", n, batches)
+	if n != 1000 || batches < 10 {
+		t.Errorf("got %d rows in %d batches, wanted 1000 rows in at least 10", n, batches)
+	}
+}
+
+func TestRetryTx(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("RetryTx"), 30*time.Second)
+	defer cancel()
+	const raiseDeadlock = "DECLARE e EXCEPTION; PRAGMA EXCEPTION_INIT(e, -60); BEGIN RAISE e; END;"
+	var n int
+	if err := godror.RetryTx(ctx, testDb, func(tx *sql.Tx) error {
+		n++
+		if n == 1 {
+			_, err := tx.ExecContext(ctx, raiseDeadlock)
+			if godror.ClassifyError(err) != godror.ClassDeadlock {
+				t.Errorf("wanted deadlock, got %+v", err)
+			}
+			return err
+		}
+		var i int
+		return tx.QueryRowContext(ctx, "SELECT 1 FROM DUAL").Scan(&i)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("got %d attempts, wanted 2", n)
+	}
+}
+
+func TestOraErrKinds(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("OraErrKinds"), 30*time.Second)
+	defer cancel()
+	const tbl = "test_oraerr_kinds"
+	_, _ = testDb.ExecContext(ctx, "DROP TABLE "+tbl)
+	if _, err := testDb.ExecContext(ctx, "CREATE TABLE "+tbl+" (id NUMBER(3) CONSTRAINT "+tbl+"_pk PRIMARY KEY, txt VARCHAR2(3) NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _, _ = testDb.ExecContext(context.Background(), "DROP TABLE "+tbl) }()
+	const ins = "INSERT INTO " + tbl + " (id, txt) VALUES (:1, :2)"
+	if _, err := testDb.ExecContext(ctx, ins, 1, "a"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		kind  error
+		check func(godror.OraErrDetails) bool
+		args  []interface{}
+	}{
+		{kind: godror.ErrUniqueViolation, args: []interface{}{1, "b"},
+			check: func(d godror.OraErrDetails) bool { return strings.HasSuffix(d.Constraint, strings.ToUpper(tbl)+"_PK") }},
+		{kind: godror.ErrNotNullViolation, args: []interface{}{2, ""},
+			check: func(d godror.OraErrDetails) bool { return d.Table == strings.ToUpper(tbl) && d.Column == "TXT" }},
+		{kind: godror.ErrValueTooLarge, args: []interface{}{3, "abcd"},
+			check: func(d godror.OraErrDetails) bool { return d.Column == "TXT" && d.MaxLength == 3 }},
+	} {
+		_, err := testDb.ExecContext(ctx, ins, tc.args...)
+		if !errors.Is(err, tc.kind) {
+			t.Errorf("%v: got %+v", tc.kind, err)
+			continue
+		}
+		oe, _ := godror.AsOraErr(err)
+		if d := oe.Details(); !tc.check(d) {
+			t.Errorf("%v: got details %+v", err, d)
+		}
+	}
+	_, err := testDb.ExecContext(ctx, "SELECT 1 FROM "+tbl+"_nonexistent")
+	if !errors.Is(err, godror.ErrObjectNotExist) {
+		t.Errorf("wanted ErrObjectNotExist, got %+v", err)
+	}
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+type testSpan struct {
+	attrs     map[string]string
+	err       error
+	operation string
+	ended     bool
+}
+
+func (t *testTracer) Start(ctx context.Context, operation string, attrs ...slog.Attr) (context.Context, godror.Span) {
+	s := &testSpan{operation: operation, attrs: make(map[string]string)}
+	s.SetAttributes(attrs...)
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return ctx, s
+}
+func (s *testSpan) SetAttributes(attrs ...slog.Attr) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value.String()
+	}
+}
+func (s *testSpan) RecordError(err error) { s.err = err }
+func (s *testSpan) End()                  { s.ended = true }
+func (s *testSpan) TraceID() string       { return "0af7651916cd43dd8448eb211c80319c" }
+
+func TestTracing(t *testing.T) {
+	t.Parallel()
+	var tr testTracer
+	ctx, cancel := context.WithTimeout(godror.ContextWithTracing(testContext("Tracing"), godror.TracingOptions{
+		Tracer:                    &tr,
+		SanitizeSQL:               func(s string) string { return strings.ReplaceAll(s, "'secret'", "?") },
+		TraceIDAsClientIdentifier: true,
+	}), 30*time.Second)
+	defer cancel()
+
+	tx, err := testDb.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	var clientID string
+	if err = tx.QueryRowContext(ctx, "SELECT SYS_CONTEXT('USERENV', 'CLIENT_IDENTIFIER') FROM DUAL WHERE 'secret' IS NOT NULL").Scan(&clientID); err != nil {
+		t.Fatal(err)
+	}
+	var clob string
+	if err = tx.QueryRowContext(ctx, "SELECT TO_CLOB('lob') FROM DUAL").Scan(&clob); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tx.ExecContext(ctx, "SELECT 1 FROM nonexistent_table_for_tracing"); err == nil {
+		t.Error("wanted error")
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	ops := make(map[string]*testSpan)
+	for _, s := range tr.spans {
+		t.Logf("%s: %v ended=%t err=%v", s.operation, s.attrs, s.ended, s.err)
+		if !s.ended {
+			t.Errorf("%s span not ended", s.operation)
+		}
+		if s.attrs[godror.TraceAttrSystem] != "oracle" {
+			t.Errorf("%s: got db.system=%q", s.operation, s.attrs[godror.TraceAttrSystem])
+		}
+		ops[s.operation] = s
+	}
+	for _, op := range []string{"Prepare", "Query", "Fetch", "LobRead", "Commit"} {
+		if ops[op] == nil {
+			t.Errorf("no %s span", op)
+		}
+	}
+	if s := ops["Query"]; s != nil && strings.Contains(s.attrs[godror.TraceAttrStatement], "secret") {
+		t.Errorf("statement is not sanitized: %q", s.attrs[godror.TraceAttrStatement])
+	}
+	if s := ops["Prepare"]; s == nil || s.err == nil || s.attrs[godror.TraceAttrErrorCode] != "942" {
+		t.Errorf("wanted ORA-00942 in the last Prepare span, got %+v", s)
+	}
+	if clientID != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("got CLIENT_IDENTIFIER %q", clientID)
+	}
+}
+
+func TestECID(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("ECID"), 10*time.Second)
+	defer cancel()
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx, err := godror.ContextWithTraceparent(ctx, "00-"+traceID+"-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ecid sql.NullString
+	const qry = "SELECT ecid FROM v$session WHERE sid = SYS_CONTEXT('USERENV', 'SID')"
+	if err = testDb.QueryRowContext(ctx, qry).Scan(&ecid); err != nil {
+		t.Skip(qry, err)
+	}
+	if ecid.String != traceID {
+		t.Errorf("got ECID %q, wanted %q", ecid.String, traceID)
+	}
+
+	// The next request on the same standalone connection must not inherit the ECID.
+	P, err := godror.ParseDSN(testConStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	P.StandaloneConnection = true
+	db := sql.OpenDB(godror.NewConnector(P))
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	if err = db.QueryRowContext(ctx, qry).Scan(&ecid); err != nil {
+		t.Fatal(qry, err)
+	}
+	if ecid.String != traceID {
+		t.Errorf("standalone: got ECID %q, wanted %q", ecid.String, traceID)
+	}
+	if err = db.QueryRowContext(context.Background(), qry).Scan(&ecid); err != nil {
+		t.Fatal(qry, err)
+	}
+	if ecid.String != "" {
+		t.Errorf("standalone: the next request got ECID %q, wanted none", ecid.String)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	P, err := godror.ParseDSN(testConStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var calls []godror.InterceptedCall
+	errForbidden := errors.New("forbidden")
+	P.Interceptors = []godror.Interceptor{
+		func(ctx context.Context, call *godror.InterceptedCall, next func(context.Context) error) error {
+			err := next(ctx)
+			mu.Lock()
+			calls = append(calls, *call)
+			mu.Unlock()
+			return err
+		},
+		func(ctx context.Context, call *godror.InterceptedCall, next func(context.Context) error) error {
+			if call.Operation == "Prepare" {
+				if strings.Contains(call.SQL, "forbidden") {
+					return errForbidden
+				}
+				call.SQL = strings.Replace(call.SQL, ":tenant", "'t1'", 1)
+			}
+			return next(ctx)
+		},
+	}
+	db := sql.OpenDB(godror.NewConnector(P))
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(testContext("Interceptors"), 30*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	var tenant string
+	if err = tx.QueryRowContext(ctx, "SELECT :tenant FROM DUAL WHERE 1 = :1", 1).Scan(&tenant); err != nil {
+		t.Fatal(err)
+	}
+	if tenant != "t1" {
+		t.Errorf("got %q, wanted t1", tenant)
+	}
+	if _, err = tx.ExecContext(ctx, "SELECT 'forbidden' FROM DUAL"); !errors.Is(err, errForbidden) {
+		t.Errorf("got %v, wanted %v", err, errForbidden)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var ops []string
+	for _, c := range calls {
+		t.Logf("%s %q %v: %s", c.Operation, c.SQL, c.Args, c.Duration)
+		ops = append(ops, c.Operation)
+		if c.Operation == "Query" && len(c.Args) != 1 {
+			t.Errorf("Query got args %v, wanted 1", c.Args)
+		}
+	}
+	if got, want := strings.Join(ops, ","), "Prepare,Query,Prepare,Commit"; got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+}
+
+func TestSlowQuery(t *testing.T) {
+	P, err := godror.ParseDSN(testConStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf syncBuffer
+	P.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	P.SlowQueryThreshold = time.Nanosecond
+	db := sql.OpenDB(godror.NewConnector(P))
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var sqlID string
+	var n int
+	if err = db.QueryRowContext(ctx, "SELECT COUNT(0) FROM all_objects WHERE ROWNUM <= :1", 10, godror.CaptureSQLID(&sqlID)).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	t.Log("sqlID:", sqlID)
+	if len(sqlID) != 13 {
+		t.Errorf("got SQL_ID %q, wanted 13 characters", sqlID)
+	}
+	if _, err = db.ExecContext(ctx, "BEGIN NULL; END;"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	t.Log(out)
+	for _, want := range []string{"operation=Query", "operation=Exec", "binds=int", "sqlID=" + sqlID} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%q not logged", want)
+		}
+	}
+	if strings.Contains(out, "planHashValue") || strings.Contains(out, "planError") {
+		t.Error("V$SQL is queried without SlowQueryPlan")
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestExecutionPlan(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("ExecutionPlan"), 30*time.Second)
+	defer cancel()
+	const qry = "SELECT /*+ GATHER_PLAN_STATISTICS */ COUNT(0) FROM user_objects WHERE object_type = :1"
+
+	root, err := godror.ExplainPlan(ctx, testDb, qry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log("plan:\n" + root.String())
+	if root.Operation != "SELECT STATEMENT" || len(root.Children) == 0 {
+		t.Errorf("got root %+v", root)
+	}
+
+	conn, err := testDb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var n int
+	var sqlID string
+	if err = conn.QueryRowContext(ctx, qry, "TABLE", godror.CaptureSQLID(&sqlID)).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := godror.DisplayCursor(ctx, conn, "", -1, "")
+	if err != nil {
+		t.Skip(err)
+	}
+	t.Log("cursor:\n" + plan)
+	if sqlID != "" && !strings.Contains(plan, sqlID) {
+		t.Errorf("plan does not contain the SQL_ID %q", sqlID)
+	}
+
+	report, err := godror.SQLMonitorReport(ctx, conn, sqlID, godror.SQLMonitorText)
+	if err != nil {
+		t.Log("SQL Monitor:", err)
+	} else {
+		t.Log("SQL Monitor:\n" + report)
+	}
+}
+
+func TestOCIAttr(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(testContext("OCIAttr"), 10*time.Second)
+	defer cancel()
+	const (
+		ociAttrCallTimeout = 531 // OCI_ATTR_CALL_TIMEOUT, ub4 milliseconds of the service context
+		ociAttrSQLID       = 504 // OCI_ATTR_SQL_ID, text of the statement
+	)
+	if err := godror.Raw(ctx, testDb, func(c godror.Conn) error {
+		conn, ok := c.(godror.OCIAttrConn)
+		if !ok {
+			t.Fatalf("%T is not an OCIAttrConn", c)
+		}
+		orig, err := conn.GetOCIAttr(godror.OCIHandleService, ociAttrCallTimeout, godror.OCIAttrUint32)
+		if err != nil {
+			return err
+		}
+		if err = conn.SetOCIAttr(godror.OCIHandleService, ociAttrCallTimeout, uint32(12345)); err != nil {
+			return err
+		}
+		got, err := conn.GetOCIAttr(godror.OCIHandleService, ociAttrCallTimeout, godror.OCIAttrUint32)
+		if err != nil {
+			return err
+		}
+		if got != uint32(12345) {
+			t.Errorf("got call timeout %v, wanted 12345", got)
+		}
+		if err = conn.SetOCIAttr(godror.OCIHandleService, ociAttrCallTimeout, orig); err != nil {
+			return err
+		}
+		if err = conn.SetOCIAttr(godror.OCIHandleService, ociAttrCallTimeout, 1); err == nil {
+			t.Error("wanted error for int value")
+		}
+
+		stmt, err := c.PrepareContext(ctx, "SELECT 1 FROM DUAL")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		sqlID, err := stmt.(godror.OCIAttrStmt).GetOCIAttr(ociAttrSQLID, godror.OCIAttrText)
+		if err != nil {
+			return err
+		}
+		t.Log("sqlID:", sqlID)
+		if s, _ := sqlID.(string); len(s) != 13 {
+			t.Errorf("got SQL_ID %q", sqlID)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionTag(t *testing.T) {
+	P, err := godror.ParseDSN(testConStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if P.StandaloneConnection {
+		t.Skip("session tags need a pool")
+	}
+	P.MinSessions, P.MaxSessions, P.SessionIncrement = 1, 1, 0
+	var mu sync.Mutex
+	var calls []string
+	P.OnSessionTag = func(ctx context.Context, conn driver.ConnPrepareContext, requested, actual string) error {
+		mu.Lock()
+		calls = append(calls, actual+"->"+requested)
+		mu.Unlock()
+		st, err := conn.PrepareContext(ctx, "ALTER SESSION SET NLS_DATE_FORMAT = 'YYYY-MM-DD'")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		_, err = st.(driver.StmtExecContext).ExecContext(ctx, nil)
+		return err
+	}
+	db := sql.OpenDB(godror.NewConnector(P))
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(testContext("SessionTag"), 30*time.Second)
+	defer cancel()
+	ctx = godror.ContextWithSessionTag(ctx, "NLS_DATE_FORMAT=YYYY-MM-DD", false)
+
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var format string
+		err = conn.QueryRowContext(ctx, "SELECT value FROM nls_session_parameters WHERE parameter = 'NLS_DATE_FORMAT'").Scan(&format)
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != "YYYY-MM-DD" {
+			t.Errorf("%d. got NLS_DATE_FORMAT %q", i, format)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	t.Log("calls:", calls)
+	if len(calls) != 1 {
+		t.Errorf("OnSessionTag called %d times, wanted once", len(calls))
+	}
+}
+
+func TestSessionTagOnInit(t *testing.T) {
+	P, err := godror.ParseDSN(testConStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if P.StandaloneConnection {
+		t.Skip("session tags need a pool")
+	}
+	P.MinSessions, P.MaxSessions, P.SessionIncrement = 1, 1, 0
+	var inits int32
+	P.OnInit = func(ctx context.Context, conn driver.ConnPrepareContext) error {
+		atomic.AddInt32(&inits, 1)
+		return nil
+	}
+	db := sql.OpenDB(godror.NewConnector(P))
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(testContext("SessionTagOnInit"), 30*time.Second)
+	defer cancel()
+	ctx = godror.ContextWithSessionTag(ctx, "godror=OnInit", false)
+
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = conn.PingContext(ctx)
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&inits); n != 1 {
+		t.Errorf("OnInit called %d times, wanted once", n)
+	}
+}
+
+func TestDRCP(t *testing.T) {
+	P, err := godror.ParseDSN(testConStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if P.StandaloneConnection {
+		t.Skip("the acquisition statistics need a pool")
+	}
+	P.ConnClass = "godrorDRCP"
+	db := sql.OpenDB(godror.NewConnector(P))
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(testContext("DRCP"), 30*time.Second)
+	defer cancel()
+
+	poolStats := func() godror.PoolStats {
+		var stats godror.PoolStats
+		if err := godror.Raw(ctx, db, func(c godror.Conn) error {
+			var err error
+			stats, err = c.GetPoolStats()
+			return err
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return stats
+	}
+	before := poolStats()
+	dctx := godror.ContextWithDRCP(ctx, "godrorDRCP", godror.PurityNew)
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(dctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = conn.PingContext(dctx)
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	after := poolStats()
+	t.Logf("before: %s\nafter: %s", before.Acquires, after.Acquires)
+	if after.Acquires.Requests < before.Acquires.Requests+3 {
+		t.Errorf("got %d acquires, wanted at least %d", after.Acquires.Requests, before.Acquires.Requests+3)
+	}
+	if after.Acquires.Reused+after.Acquires.New != after.Acquires.Requests {
+		t.Errorf("reused+new != acquires: %s", after.Acquires)
+	}
+	if after.DRCPClass == "" {
+		t.Log("no DRCP statistics (not DRCP, or V$CPOOL_CC_STATS is not readable)")
+	} else if after.DRCP.Requests < before.DRCP.Requests+3 || after.DRCP.Hits+after.DRCP.Misses > after.DRCP.Requests {
+		t.Errorf("DRCP statistics of %s: before %s, after %s", after.DRCPClass, before.DRCP, after.DRCP)
+	}
+
+	classStats, err := godror.DRCPClassStats(ctx, db)
+	if err != nil {
+		t.Skip(err)
+	}
+	t.Log("V$CPOOL_CC_STATS:", classStats)
+}